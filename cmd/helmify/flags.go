@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/sirupsen/logrus"
 )
 
 const helpText = `Helmify parses kubernetes resources from std.in and converts it to a Helm chart.
@@ -53,8 +54,13 @@ func (i *arrayFlags) Set(value string) error {
 // ReadFlags command-line flags into app config.
 func ReadFlags() config.Config {
 	files := arrayFlags{}
+	sources := arrayFlags{}
+	maintainers := arrayFlags{}
+	processors := arrayFlags{}
+	var keywords, configFile string
 	result := config.Config{}
 	var h, help, version, crd, preservens bool
+	flag.StringVar(&configFile, "config", "", "Helmify config file. Flags override config file values. Defaults to "+config.FileName+" in working directory or "+config.ChartFileName+" in chart directory if exists. Example: helmify -config=helmify.yaml")
 	flag.BoolVar(&h, "h", false, "Print help. Example: helmify -h")
 	flag.BoolVar(&help, "help", false, "Print help. Example: helmify -help")
 	flag.BoolVar(&version, "version", false, "Print helmify version. Example: helmify -version")
@@ -62,7 +68,7 @@ func ReadFlags() config.Config {
 	flag.BoolVar(&result.VeryVerbose, "vv", false, "Enable very verbose output. Same as verbose but with DEBUG. Example: helmify -vv")
 	flag.BoolVar(&crd, "crd-dir", false, "Enable crd install into 'crds' directory.\nWarning: CRDs placed in 'crds' directory will not be templated by Helm.\nSee https://helm.sh/docs/chart_best_practices/custom_resource_definitions/#some-caveats-and-explanations\nExample: helmify -crd-dir")
 	flag.BoolVar(&result.ImagePullSecrets, "image-pull-secrets", false, "Allows the user to use existing secrets as imagePullSecrets in values.yaml")
-	flag.BoolVar(&result.GenerateDefaults, "generate-defaults", false, "Allows the user to add empty placeholders for typical customization options in values.yaml. Currently covers: topology constraints, node selectors, tolerances")
+	flag.BoolVar(&result.GenerateDefaults, "generate-defaults", false, "Allows the user to add empty placeholders for typical customization options in values.yaml. Currently covers: node selectors, tolerations, affinity, topology spread constraints, priority class, pod labels and annotations")
 	flag.BoolVar(&result.CertManagerAsSubchart, "cert-manager-as-subchart", false, "Allows the user to add cert-manager as a subchart")
 	flag.StringVar(&result.CertManagerVersion, "cert-manager-version", "v1.12.2", "Allows the user to specify cert-manager subchart version. Only useful with cert-manager-as-subchart.")
 	flag.BoolVar(&result.CertManagerInstallCRD, "cert-manager-install-crd", true, "Allows the user to install cert-manager CRD. Only useful with cert-manager-as-subchart.")
@@ -70,7 +76,26 @@ func ReadFlags() config.Config {
 	flag.BoolVar(&result.OriginalName, "original-name", false, "Use the object's original name instead of adding the chart's release name as the common prefix.")
 	flag.Var(&files, "f", "File or directory containing k8s manifests")
 	flag.BoolVar(&preservens, "preserve-ns", false, "Use the object's original namespace instead of adding all the resources to a common namespace")
+	flag.BoolVar(&result.CreateNamespace, "create-namespace", false, "Add Namespace objects to the chart guarded by createNamespace value. Example: helmify -create-namespace")
+	flag.StringVar((*string)(&result.NamespaceMode), "namespace-mode", "", "Namespace of objects: 'omit' (default), 'preserve' (same as -preserve-ns), 'release' sets {{ .Release.Namespace }}, 'values' moves every namespace into values.yaml. Example: helmify -namespace-mode=values")
+	flag.StringVar((*string)(&result.EnvMode), "env-mode", "", "Container env variables in values.yaml: 'map' (default) moves plain values keyed by variable name, 'list' moves the whole env list including valueFrom. Example: helmify -env-mode=list")
+	flag.BoolVar(&result.ClusterDomainEnv, "cluster-domain-env", false, "Add KUBERNETES_CLUSTER_DOMAIN env variable set from kubernetesClusterDomain value to every container. Example: helmify -cluster-domain-env")
 	flag.BoolVar(&result.AddWebhookOption, "add-webhook-option", false, "Allows the user to add webhook option in values.yaml")
+	flag.BoolVar(&result.MergeValues, "merge-values", false, "Merge generated values with existing values.yaml keeping values changed or added by the user. Example: helmify -merge-values")
+	flag.BoolVar(&result.DryRun, "dry-run", false, "Print unified diff between generated and existing chart without writing it. Exits with non-zero code if chart is changed. Example: helmify -dry-run")
+	flag.BoolVar(&result.Package, "package", false, "Write chart as CHART_NAME-VERSION.tgz archive instead of chart directory. Example: helmify -package mychart")
+	flag.BoolVar(&result.LiftSpec, "lift-spec", false, "Move spec of resources unknown to helmify into values.yaml. Example: helmify -lift-spec")
+	flag.BoolVar(&result.ValuesSchema, "values-schema", false, "Generate values.schema.json to validate values overrides. Example: helmify -values-schema")
+	flag.StringVar(&result.Chart.Version, "chart-version", "", "Set chart version in Chart.yaml. Example: helmify -chart-version=1.2.0")
+	flag.StringVar(&result.Chart.AppVersion, "app-version", "", "Set appVersion in Chart.yaml. Defaults to the most common image tag for a new chart. Example: helmify -app-version=v1.2.0")
+	flag.StringVar(&result.Chart.Description, "chart-description", "", "Set chart description in Chart.yaml")
+	flag.StringVar(&result.Chart.Home, "chart-home", "", "Set project home page URL in Chart.yaml")
+	flag.StringVar(&result.Chart.Icon, "chart-icon", "", "Set chart icon URL in Chart.yaml")
+	flag.StringVar(&result.Chart.KubeVersion, "kube-version", "", "Set supported Kubernetes versions constraint in Chart.yaml. Example: helmify -kube-version='>=1.23.0-0'")
+	flag.StringVar(&keywords, "chart-keywords", "", "Comma-separated chart keywords in Chart.yaml. Example: helmify -chart-keywords=operator,database")
+	flag.Var(&sources, "chart-source", "Project source code URL in Chart.yaml, multiple sources supported")
+	flag.Var(&maintainers, "chart-maintainer", "Chart maintainer in Chart.yaml in format 'name[,email[,url]]', multiple maintainers supported. Example: helmify -chart-maintainer='John Doe,john@example.com'")
+	flag.Var(&processors, "processor", "External processor executable, multiple processors supported. Takes precedence over built-in processors. Example: helmify -processor=./my-crd-processor")
 
 	flag.Parse()
 	if h || help {
@@ -82,6 +107,19 @@ func ReadFlags() config.Config {
 		printVersion()
 		os.Exit(0)
 	}
+	if configFile == "" {
+		configFile = lookupConfigFile(flag.Arg(0))
+	}
+	if configFile != "" {
+		err := result.LoadFile(configFile)
+		if err != nil {
+			logrus.WithError(err).Error("unable to load config file")
+			os.Exit(1)
+		}
+		// parse flags again to override config file values with flags set explicitly
+		files, sources, maintainers, processors = files[:0], sources[:0], maintainers[:0], processors[:0]
+		_ = flag.CommandLine.Parse(os.Args[1:])
+	}
 	name := flag.Arg(0)
 	if name != "" {
 		result.ChartName = filepath.Base(name)
@@ -93,6 +131,49 @@ func ReadFlags() config.Config {
 	if preservens {
 		result.PreserveNs = true
 	}
-	result.Files = files
+	if len(files) != 0 {
+		result.Files = files
+	}
+	if keywords != "" {
+		result.Chart.Keywords = nil
+		for _, k := range strings.Split(keywords, ",") {
+			result.Chart.Keywords = append(result.Chart.Keywords, strings.TrimSpace(k))
+		}
+	}
+	if len(sources) != 0 {
+		result.Chart.Sources = sources
+	}
+	if len(maintainers) != 0 {
+		result.Chart.Maintainers = nil
+		for _, m := range maintainers {
+			result.Chart.Maintainers = append(result.Chart.Maintainers, parseMaintainer(m))
+		}
+	}
+	for _, p := range processors {
+		result.Processors = append(result.Processors, config.ExternalProcessor{Command: p})
+	}
 	return result
 }
+
+// parseMaintainer parses maintainer in format 'name[,email[,url]]'.
+func parseMaintainer(value string) config.Maintainer {
+	parts := strings.SplitN(value, ",", 3)
+	res := config.Maintainer{Name: strings.TrimSpace(parts[0])}
+	if len(parts) > 1 {
+		res.Email = strings.TrimSpace(parts[1])
+	}
+	if len(parts) > 2 {
+		res.URL = strings.TrimSpace(parts[2])
+	}
+	return res
+}
+
+// lookupConfigFile returns config file from working directory or from chart directory if exists.
+func lookupConfigFile(chart string) string {
+	for _, file := range []string{config.FileName, config.ChartConfigFile(chart)} {
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return ""
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/EdgeGamingGG/helmify/pkg/app"
+	"github.com/EdgeGamingGG/helmify/pkg/helm"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,7 +20,12 @@ func main() {
 		logrus.Error("no data piped in stdin")
 		os.Exit(1)
 	}
-	if err = app.Start(os.Stdin, conf); err != nil {
+	err = app.Start(os.Stdin, conf)
+	if errors.Is(err, helm.ErrChartChanged) {
+		// diff is already printed
+		os.Exit(1)
+	}
+	if err != nil {
 		logrus.WithError(err).Error("helmify finished with error")
 		os.Exit(1)
 	}
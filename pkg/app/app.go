@@ -5,18 +5,27 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/EdgeGamingGG/helmify/pkg/file"
+	"github.com/EdgeGamingGG/helmify/pkg/processor/external"
+	"github.com/EdgeGamingGG/helmify/pkg/processor/gateway"
+	"github.com/EdgeGamingGG/helmify/pkg/processor/horizontalpodautoscaler"
 	"github.com/EdgeGamingGG/helmify/pkg/processor/job"
+	"github.com/EdgeGamingGG/helmify/pkg/processor/namespace"
+	"github.com/EdgeGamingGG/helmify/pkg/processor/networkpolicy"
 	"github.com/EdgeGamingGG/helmify/pkg/processor/poddisruptionbudget"
+	"github.com/EdgeGamingGG/helmify/pkg/processor/rollout"
 	"github.com/EdgeGamingGG/helmify/pkg/processor/statefulset"
 
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/decoder"
 	"github.com/EdgeGamingGG/helmify/pkg/helm"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 	"github.com/EdgeGamingGG/helmify/pkg/processor"
 	"github.com/EdgeGamingGG/helmify/pkg/processor/configmap"
 	"github.com/EdgeGamingGG/helmify/pkg/processor/crd"
@@ -45,16 +54,93 @@ func Start(stdin io.Reader, config config.Config) error {
 		logrus.Debug("Received termination, signaling shutdown")
 		cancelFunc()
 	}()
-	appCtx := New(config, helm.NewOutput())
-	appCtx = appCtx.WithProcessors(
+	output := helm.NewOutput()
+	switch {
+	case config.DryRun:
+		output = helm.NewDryRunOutput(os.Stdout)
+	case config.Package:
+		output = helm.NewPackageOutput()
+	}
+	appCtx := newContext(config, output)
+	if len(config.Files) != 0 {
+		file.Walk(config.Files, config.FilesRecursively, func(filename string, fileReader io.Reader) {
+			objects := decoder.Decode(ctx.Done(), fileReader)
+			for obj := range objects {
+				appCtx.Add(obj, filename)
+			}
+		})
+	} else {
+		objects := decoder.Decode(ctx.Done(), stdin)
+		for obj := range objects {
+			appCtx.Add(obj, "")
+		}
+	}
+
+	return appCtx.CreateHelm(ctx.Done())
+}
+
+// Create converts k8s objects into Helm chart and writes it with the given output.
+// Unlike Start it does not read input, handle OS signals or change log level, so it can be used as a library.
+func Create(ctx context.Context, conf config.Config, output helmify.Output, objects []*unstructured.Unstructured) error {
+	err := conf.Validate()
+	if err != nil {
+		return err
+	}
+	appCtx := newContext(conf, output)
+	for _, obj := range objects {
+		appCtx.Add(obj, "")
+	}
+	err = appCtx.CreateHelm(ctx.Done())
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// Chart converts k8s objects into Helm chart kept in memory. See Create for details.
+func Chart(ctx context.Context, conf config.Config, objects []*unstructured.Unstructured) (helm.Chart, error) {
+	output := helm.NewMemoryOutput()
+	err := Create(ctx, conf, output, objects)
+	if err != nil {
+		return helm.Chart{}, err
+	}
+	return output.Chart(), nil
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []helmify.Processor
+)
+
+// Register adds processors used by Start, Create and Chart. Registered processors take precedence over
+// built-in ones, so they can be used to support custom resources or to override processing of known kinds.
+func Register(processors ...helmify.Processor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, processors...)
+}
+
+// newContext returns context with registered, external and all built-in processors.
+func newContext(conf config.Config, output helmify.Output) *appContext {
+	registryMu.RLock()
+	appCtx := New(conf, output).WithProcessors(registry...)
+	registryMu.RUnlock()
+	for _, p := range conf.Processors {
+		appCtx.WithProcessors(external.New(p))
+	}
+	return appCtx.WithProcessors(
 		configmap.New(),
 		crd.New(),
 		daemonset.New(),
 		deployment.New(),
+		rollout.New(),
 		statefulset.New(),
 		storage.New(),
 		service.New(),
 		service.NewIngress(),
+		gateway.New(),
+		gateway.NewHTTPRoute(),
+		gateway.NewGRPCRoute(),
 		rbac.ClusterRoleBinding(),
 		rbac.Role(),
 		rbac.RoleBinding(),
@@ -67,22 +153,10 @@ func Start(stdin io.Reader, config config.Config) error {
 		job.NewCron(),
 		job.NewJob(),
 		poddisruptionbudget.New(),
+		horizontalpodautoscaler.New(),
+		networkpolicy.New(),
+		namespace.New(),
 	).WithDefaultProcessor(processor.Default())
-	if len(config.Files) != 0 {
-		file.Walk(config.Files, config.FilesRecursively, func(filename string, fileReader io.Reader) {
-			objects := decoder.Decode(ctx.Done(), fileReader)
-			for obj := range objects {
-				appCtx.Add(obj, filename)
-			}
-		})
-	} else {
-		objects := decoder.Decode(ctx.Done(), stdin)
-		for obj := range objects {
-			appCtx.Add(obj, "")
-		}
-	}
-
-	return appCtx.CreateHelm(ctx.Done())
 }
 
 func setLogLevel(config config.Config) {
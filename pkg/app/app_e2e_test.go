@@ -2,12 +2,18 @@ package app
 
 import (
 	"bufio"
+	"context"
+	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/decoder"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 	"github.com/stretchr/testify/assert"
 	"helm.sh/helm/v3/pkg/action"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 const (
@@ -42,7 +48,7 @@ func TestApp(t *testing.T) {
 	assert.NoError(t, err)
 
 	objects := bufio.NewReader(file)
-	err = Start(objects, config.Config{ChartName: appChartName})
+	err = Start(objects, config.Config{ChartName: appChartName, ValuesSchema: true})
 	assert.NoError(t, err)
 
 	t.Cleanup(func() {
@@ -58,3 +64,107 @@ func TestApp(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestChart(t *testing.T) {
+	file, err := os.Open("../../test_data/sample-app.yaml")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var objects []*unstructured.Unstructured
+	for obj := range decoder.Decode(context.Background().Done(), file) {
+		objects = append(objects, obj)
+	}
+	chart, err := Chart(context.Background(), config.Config{ChartName: appChartName}, objects)
+	assert.NoError(t, err)
+
+	_, err = os.Stat(appChartName)
+	assert.True(t, os.IsNotExist(err), "chart must not be written to filesystem")
+	assert.Equal(t, appChartName, chart.Name)
+	assert.Contains(t, chart.Files, "Chart.yaml")
+	assert.Contains(t, chart.Files, "templates/deployment.yaml")
+	assert.Contains(t, chart.Values, "myapp")
+
+	t.Run("object options", func(t *testing.T) {
+		conf := config.Config{ChartName: appChartName}
+		err := conf.Load([]byte(`
+kinds:
+  Secret:
+    skip: true
+objects:
+  - kind: Deployment
+    name: myapp
+    filename: app.yaml
+`))
+		assert.NoError(t, err)
+		chart, err := Chart(context.Background(), conf, objects)
+		assert.NoError(t, err)
+		assert.NotContains(t, chart.Files, "templates/my-secret-ca.yaml")
+		assert.NotContains(t, chart.Values, "mySecretVars")
+		assert.Contains(t, string(chart.Files["templates/app.yaml"]), "kind: Deployment")
+	})
+
+	t.Run("toggles", func(t *testing.T) {
+		conf := config.Config{ChartName: appChartName}
+		err := conf.Load([]byte(`
+kinds:
+  PodDisruptionBudget:
+    toggle: true
+objects:
+  - kind: CronJob
+    name: cron-job
+    toggle: true
+`))
+		assert.NoError(t, err)
+		chart, err := Chart(context.Background(), conf, objects)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(chart.Files["templates/cron-job.yaml"]), "{{- if .Values.cronJobCronJob.enabled }}\n"))
+		assert.True(t, strings.HasSuffix(string(chart.Files["templates/cron-job.yaml"]), "{{- end }}\n"))
+		assert.Contains(t, string(chart.Files["templates/pdb.yaml"]), "{{- if .Values.pdbPodDisruptionBudget.enabled }}")
+		assert.False(t, strings.HasPrefix(string(chart.Files["templates/deployment.yaml"]), "{{- if"))
+		enabled, _, _ := unstructured.NestedBool(chart.Values, "cronJobCronJob", "enabled")
+		assert.True(t, enabled)
+		assert.Contains(t, chart.Values["cronJob"], "schedule", "processor values must be kept")
+		assert.NotContains(t, chart.Values["cronJob"], "enabled")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = Chart(ctx, config.Config{ChartName: appChartName}, objects)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+type testProcessor struct{}
+
+func (testProcessor) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GetKind() != "MyResource" {
+		return false, nil, nil
+	}
+	return true, testTemplate{}, nil
+}
+
+type testTemplate struct{}
+
+func (testTemplate) Filename() string { return "my-resource.yaml" }
+
+func (testTemplate) Values() helmify.Values {
+	return helmify.Values{"myResource": map[string]interface{}{"size": 1}}
+}
+
+func (testTemplate) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte("kind: MyResource"))
+	return err
+}
+
+func TestRegister(t *testing.T) {
+	Register(testProcessor{})
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("example.com/v1")
+	obj.SetKind("MyResource")
+	obj.SetName("res")
+	chart, err := Chart(context.Background(), config.Config{ChartName: appChartName}, []*unstructured.Unstructured{obj})
+	assert.NoError(t, err)
+	assert.Equal(t, "kind: MyResource\n", string(chart.Files["templates/my-resource.yaml"]))
+	assert.Contains(t, chart.Values, "myResource")
+}
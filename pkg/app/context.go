@@ -4,6 +4,9 @@ import (
 	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/EdgeGamingGG/helmify/pkg/refs"
+	"github.com/EdgeGamingGG/helmify/pkg/rules"
+	"github.com/EdgeGamingGG/helmify/pkg/toggle"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -15,6 +18,7 @@ type appContext struct {
 	output           helmify.Output
 	config           config.Config
 	appMeta          *metadata.Service
+	refs             *refs.Graph
 	objects          []*unstructured.Unstructured
 	fileNames        []string
 }
@@ -24,6 +28,7 @@ func New(config config.Config, output helmify.Output) *appContext {
 	return &appContext{
 		config:  config,
 		appMeta: metadata.New(config),
+		refs:    refs.New(),
 		output:  output,
 	}
 }
@@ -42,8 +47,16 @@ func (c *appContext) WithDefaultProcessor(processor helmify.Processor) *appConte
 
 // Add k8s object to app context.
 func (c *appContext) Add(obj *unstructured.Unstructured, filename string) {
+	if c.config.ObjectOptions(qualifiedKind(obj), obj.GetName()).Skipped() {
+		logrus.WithFields(logrus.Fields{
+			"Kind": obj.GetKind(),
+			"Name": obj.GetName(),
+		}).Info("Skipping: excluded by config.")
+		return
+	}
 	// we need to add all objects before start processing only to define app metadata.
 	c.appMeta.Load(obj)
+	c.refs.Load(c.config, obj)
 	c.objects = append(c.objects, obj)
 	c.fileNames = append(c.fileNames, filename)
 }
@@ -57,16 +70,38 @@ func (c *appContext) CreateHelm(stop <-chan struct{}) error {
 	var templates []helmify.Template
 	var filenames []string
 	for i, obj := range c.objects {
-		template, err := c.process(obj)
+		opts := c.config.ObjectOptions(qualifiedKind(obj), obj.GetName())
+		appMeta := c.appMeta.WithConfig(c.config.ForObject(qualifiedKind(obj), obj.GetName()))
+		// processors get a copy with templated references, value rules take original field values
+		template, err := c.process(appMeta, c.refs.Rewrite(appMeta, obj))
 		if err != nil {
 			return err
 		}
+		template, err = rules.Apply(appMeta, obj, template, opts.Values)
+		if err != nil {
+			return err
+		}
+		if opts.Toggled() {
+			template, err = toggle.Wrap(template, obj.GetKind(), appMeta.TrimName(obj.GetName()))
+			if err != nil {
+				return err
+			}
+		}
+		if nsValues := appMeta.NamespaceValues(); nsValues != nil && template != nil {
+			template, err = withValues(template, nsValues)
+			if err != nil {
+				return err
+			}
+		}
 		if template != nil {
 			templates = append(templates, template)
 			filename := template.Filename()
 			if c.fileNames[i] != "" {
 				filename = c.fileNames[i]
 			}
+			if opts.Filename != "" {
+				filename = opts.Filename
+			}
 			filenames = append(filenames, filename)
 		}
 		select {
@@ -75,12 +110,21 @@ func (c *appContext) CreateHelm(stop <-chan struct{}) error {
 		default:
 		}
 	}
-	return c.output.Create(c.config.ChartDir, c.config.ChartName, c.config.Crd, c.config.CertManagerAsSubchart, c.config.CertManagerVersion, c.config.CertManagerInstallCRD, templates, filenames)
+	for _, r := range c.refs.Dangling() {
+		logrus.WithFields(logrus.Fields{
+			"Kind":    r.Kind,
+			"Name":    r.Name,
+			"Path":    r.Path,
+			"RefKind": r.RefKind,
+			"RefName": r.RefName,
+		}).Warn("Reference to object missing in the input: name is not templated.")
+	}
+	return c.output.Create(c.config, templates, filenames)
 }
 
-func (c *appContext) process(obj *unstructured.Unstructured) (helmify.Template, error) {
+func (c *appContext) process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (helmify.Template, error) {
 	for _, p := range c.processors {
-		if processed, result, err := p.Process(c.appMeta, obj); processed {
+		if processed, result, err := p.Process(appMeta, obj); processed {
 			if err != nil {
 				return nil, err
 			}
@@ -100,6 +144,42 @@ func (c *appContext) process(obj *unstructured.Unstructured) (helmify.Template,
 		}).Warn("Skipping: no suitable processor for resource.")
 		return nil, nil
 	}
-	_, t, err := c.defaultProcessor.Process(c.appMeta, obj)
+	_, t, err := c.defaultProcessor.Process(appMeta, obj)
 	return t, err
 }
+
+func qualifiedKind(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	return config.QualifiedKind(gvk.Group, gvk.Kind)
+}
+
+// withValues returns template with given values merged into template values.
+func withValues(template helmify.Template, values helmify.Values) (helmify.Template, error) {
+	merged := helmify.Values{}
+	err := merged.Merge(template.Values())
+	if err != nil {
+		return nil, err
+	}
+	err = merged.Merge(values)
+	if err != nil {
+		return nil, err
+	}
+	return &valuesTemplate{Template: template, values: merged}, nil
+}
+
+type valuesTemplate struct {
+	helmify.Template
+	values helmify.Values
+}
+
+func (t *valuesTemplate) Values() helmify.Values {
+	return t.values
+}
+
+// Schema returns schema keywords of the original template.
+func (t *valuesTemplate) Schema() helmify.Schema {
+	if provider, ok := t.Template.(helmify.SchemaProvider); ok {
+		return provider.Schema()
+	}
+	return nil
+}
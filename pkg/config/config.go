@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -13,39 +14,203 @@ const defaultChartName = "chart"
 // Config for Helmify application.
 type Config struct {
 	// ChartName name of the Helm chart and its base directory where Chart.yaml is located.
-	ChartName string
+	ChartName string `json:"chartName"`
 	// ChartDir - optional path to chart dir. Full chart path will be: ChartDir/ChartName/Chart.yaml.
-	ChartDir string
+	ChartDir string `json:"chartDir"`
 	// Verbose set true to see WARN and INFO logs.
-	Verbose bool
+	Verbose bool `json:"verbose"`
 	// VeryVerbose set true to see WARN, INFO, and DEBUG logs.
-	VeryVerbose bool
+	VeryVerbose bool `json:"veryVerbose"`
 	// crd-dir set true to enable crd folder.
-	Crd bool
+	Crd bool `json:"crdDir"`
 	// ImagePullSecrets flag
-	ImagePullSecrets bool
+	ImagePullSecrets bool `json:"imagePullSecrets"`
 	// GenerateDefaults enables the generation of empty values placeholders for common customization options of helm chart
 	// current generated values: tolerances, node selectors, topology constraints
-	GenerateDefaults bool
+	GenerateDefaults bool `json:"generateDefaults"`
 	// CertManagerAsSubchart enables the generation of a subchart for cert-manager
-	CertManagerAsSubchart bool
+	CertManagerAsSubchart bool `json:"certManagerAsSubchart"`
 	// CertManagerVersion sets cert-manager version in dependency
-	CertManagerVersion string
+	CertManagerVersion string `json:"certManagerVersion"`
 	// CertManagerVersion enables installation of cert-manager CRD
-	CertManagerInstallCRD bool
+	CertManagerInstallCRD bool `json:"certManagerInstallCRD"`
 	// Files - directories or files with k8s manifests
-	Files []string
+	Files []string `json:"files"`
 	// FilesRecursively read Files recursively
-	FilesRecursively bool
+	FilesRecursively bool `json:"filesRecursively"`
 	// OriginalName retains Kubernetes resource's original name
-	OriginalName bool
-	// PreserveNs retains the namespaces on the Kubernetes manifests
-	PreserveNs bool
+	OriginalName bool `json:"originalName"`
+	// PreserveNs retains the namespaces on the Kubernetes manifests. Same as NamespacePreserve mode
+	PreserveNs bool `json:"preserveNs"`
+	// NamespaceMode defines how object namespaces are templated. Overrides PreserveNs. See Namespaces
+	NamespaceMode NamespaceMode `json:"namespaceMode"`
+	// EnvMode defines how container env variables are templated. See EnvMode
+	EnvMode EnvMode `json:"envMode"`
+	// ClusterDomainEnv adds KUBERNETES_CLUSTER_DOMAIN env variable to every container
+	ClusterDomainEnv bool `json:"clusterDomainEnv"`
+	// CreateNamespace adds Namespace objects to the chart guarded by createNamespace value
+	CreateNamespace bool `json:"createNamespace"`
 	// AddWebhookOption enables the generation of a webhook option in values.yamlß
-	AddWebhookOption bool
+	AddWebhookOption bool `json:"addWebhookOption"`
+	// ValuesSchema enables the generation of values.schema.json inferred from values.yaml
+	ValuesSchema bool `json:"valuesSchema"`
+	// MergeValues keeps user changes of existing values.yaml instead of overwriting it
+	MergeValues bool `json:"mergeValues"`
+	// DryRun prints diff between generated and existing chart instead of writing it
+	DryRun bool `json:"dryRun"`
+	// Package writes chart as <ChartName>-<version>.tgz archive into ChartDir instead of chart directory
+	Package bool `json:"package"`
+	// LiftSpec moves spec of resources unknown to helmify into values.yaml
+	LiftSpec bool `json:"liftSpec"`
+	// Chart - Chart.yaml metadata
+	Chart ChartMeta `json:"chart"`
+	// Kinds - options for objects of the given kind, e.g. "Deployment"
+	Kinds map[string]ObjectOptions `json:"kinds"`
+	// Objects - options for particular objects. Override options from Kinds
+	Objects []ObjectOptions `json:"objects"`
+	// Processors - external processors. Take precedence over built-in processors
+	Processors []ExternalProcessor `json:"processors"`
+
+	// unknownKeys - keys of the config file not matching any option
+	unknownKeys []string
+}
+
+// NamespaceMode - defines how object namespaces are templated.
+type NamespaceMode string
+
+const (
+	// NamespaceOmit omits namespace of objects, so objects are installed into release namespace.
+	NamespaceOmit NamespaceMode = "omit"
+	// NamespacePreserve keeps original namespace of objects.
+	NamespacePreserve NamespaceMode = "preserve"
+	// NamespaceRelease sets namespace of namespaced objects to {{ .Release.Namespace }}.
+	NamespaceRelease NamespaceMode = "release"
+	// NamespaceValues moves every app namespace into values.yaml under namespaces.<namespaceCamel>.
+	// Namespaced objects without namespace are placed into release namespace.
+	NamespaceValues NamespaceMode = "values"
+)
+
+// Namespaces returns namespace mode. Defaults to NamespacePreserve if PreserveNs is set and to NamespaceOmit otherwise.
+func (c Config) Namespaces() NamespaceMode {
+	switch {
+	case c.NamespaceMode != "":
+		return c.NamespaceMode
+	case c.PreserveNs:
+		return NamespacePreserve
+	default:
+		return NamespaceOmit
+	}
+}
+
+func (m NamespaceMode) validate() error {
+	switch m {
+	case "", NamespaceOmit, NamespacePreserve, NamespaceRelease, NamespaceValues:
+		return nil
+	}
+	return fmt.Errorf("invalid namespace mode %q: must be one of %s, %s, %s, %s", m, NamespaceOmit, NamespacePreserve, NamespaceRelease, NamespaceValues)
+}
+
+// EnvMode - defines how container env variables are templated.
+type EnvMode string
+
+const (
+	// EnvMap moves plain values of env variables into values.yaml under <workload>.<container>.env.<nameCamel>.
+	// Variables with valueFrom are kept in templates.
+	EnvMap EnvMode = "map"
+	// EnvList moves the whole env list including valueFrom into values.yaml under <workload>.<container>.env
+	// keeping order of variables.
+	EnvList EnvMode = "list"
+)
+
+// Env returns env mode. Defaults to EnvMap.
+func (c Config) Env() EnvMode {
+	if c.EnvMode == "" {
+		return EnvMap
+	}
+	return c.EnvMode
+}
+
+func (m EnvMode) validate() error {
+	switch m {
+	case "", EnvMap, EnvList:
+		return nil
+	}
+	return fmt.Errorf("invalid env mode %q: must be one of %s, %s", m, EnvMap, EnvList)
+}
+
+// ChartMeta - Chart.yaml metadata. Empty fields are not changed in existing Chart.yaml.
+type ChartMeta struct {
+	// Version - chart version
+	Version string `json:"version"`
+	// AppVersion - version of the app. Defaults to the most common image tag for a new chart
+	AppVersion string `json:"appVersion"`
+	// Description - chart description
+	Description string `json:"description"`
+	// Home - project home page URL
+	Home string `json:"home"`
+	// Icon - chart icon URL
+	Icon string `json:"icon"`
+	// KubeVersion - semver constraint of supported Kubernetes versions
+	KubeVersion string `json:"kubeVersion"`
+	// Keywords - chart keywords
+	Keywords []string `json:"keywords"`
+	// Sources - project source code URLs
+	Sources []string `json:"sources"`
+	// Maintainers - chart maintainers
+	Maintainers []Maintainer `json:"maintainers"`
+}
+
+// ExternalProcessor - executable converting k8s objects into templates. See pkg/processor/external for the protocol.
+type ExternalProcessor struct {
+	// Command - path to the executable
+	Command string `json:"command"`
+	// Args - command arguments
+	Args []string `json:"args"`
+	// Kinds - kinds of objects passed to the processor, e.g. "MyResource" or "example.com/MyResource".
+	// All objects are passed if empty.
+	Kinds []string `json:"kinds"`
+}
+
+// Maintainer - Chart.yaml maintainer.
+type Maintainer struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	URL   string `json:"url"`
 }
 
 func (c *Config) Validate() error {
+	if len(c.unknownKeys) != 0 {
+		return fmt.Errorf("unknown config keys: %s", strings.Join(c.unknownKeys, ", "))
+	}
+	for i, o := range c.Objects {
+		if o.Name == "" {
+			return fmt.Errorf("invalid config: objects[%d]: name is required", i)
+		}
+		if err := o.validate(); err != nil {
+			return fmt.Errorf("%w: invalid config: objects[%d]", err, i)
+		}
+	}
+	for i, p := range c.Processors {
+		if p.Command == "" {
+			return fmt.Errorf("invalid config: processors[%d]: command is required", i)
+		}
+	}
+	if err := c.NamespaceMode.validate(); err != nil {
+		return err
+	}
+	if err := c.EnvMode.validate(); err != nil {
+		return err
+	}
+	kinds := map[string]string{}
+	for kind, o := range c.Kinds {
+		if err := o.validate(); err != nil {
+			return fmt.Errorf("%w: invalid config: kinds.%s", err, kind)
+		}
+		if other, ok := kinds[strings.ToLower(kind)]; ok {
+			return fmt.Errorf("invalid config: kinds %s and %s differ only in case", other, kind)
+		}
+		kinds[strings.ToLower(kind)] = kind
+	}
 	if c.ChartName == "" {
 		logrus.Infof("Chart name is not set. Using default name '%s", defaultChartName)
 		c.ChartName = defaultChartName
@@ -0,0 +1,331 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/fieldpath"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// FileName - helmify config file looked up in the working directory.
+	FileName = "helmify.yaml"
+	// ChartFileName - helmify config file looked up in the chart directory.
+	ChartFileName = ".helmify.yaml"
+)
+
+// ChartConfigFile returns path of the config file in the chart directory.
+// chartPath is a chart directory, e.g. 'deploy/charts/mychart'. Default chart directory is used if empty.
+func ChartConfigFile(chartPath string) string {
+	if chartPath == "" {
+		chartPath = defaultChartName
+	}
+	return filepath.Join(chartPath, ChartFileName)
+}
+
+// ObjectOptions - options applied to k8s objects. Unset options are inherited from the global config.
+type ObjectOptions struct {
+	// Kind - kind of the object, optionally qualified with API group, e.g. "serving.knative.dev/Service".
+	// Used only in Config.Objects. Matches any kind if empty.
+	Kind string `json:"kind"`
+	// Name - name of the object. Used only in Config.Objects.
+	Name string `json:"name"`
+	// Skip excludes objects from the chart
+	Skip *bool `json:"skip"`
+	// Filename - name of the template file for objects
+	Filename string `json:"filename"`
+	// Toggle wraps templates of objects into {{- if .Values.<name>.enabled }} conditional. Enabled by default in values.
+	Toggle *bool `json:"toggle"`
+	// ImagePullSecrets overrides Config.ImagePullSecrets
+	ImagePullSecrets *bool `json:"imagePullSecrets"`
+	// GenerateDefaults overrides Config.GenerateDefaults
+	GenerateDefaults *bool `json:"generateDefaults"`
+	// PreserveNs overrides Config.PreserveNs
+	PreserveNs *bool `json:"preserveNs"`
+	// NamespaceMode overrides Config.NamespaceMode
+	NamespaceMode NamespaceMode `json:"namespaceMode"`
+	// EnvMode overrides Config.EnvMode
+	EnvMode EnvMode `json:"envMode"`
+	// Values - rules lifting object fields into values. Rules from Kinds and Objects are combined.
+	Values []ValueRule `json:"values"`
+	// PodSpecs - paths of pod specs embedded into objects unknown to helmify, e.g. spec.jobTargetRef.template.spec.
+	// Pod specs are templated the same way as for Deployment. Defaults to spec.template.spec if it has containers.
+	// Empty list disables pod spec templating.
+	PodSpecs []string `json:"podSpecs"`
+	// Lift - top-level fields of resources unknown to helmify moved into values.yaml, e.g. [spec, data].
+	// Overrides Config.LiftSpec. Empty list disables lifting.
+	Lift []string `json:"lift"`
+	// References - fields of objects holding names of other objects, e.g. spec.secretName of a custom resource.
+	// Referenced chart objects are renamed consistently with built-in references. References from Kinds and Objects are combined.
+	References []Reference `json:"references"`
+}
+
+// Reference - object field holding name of another object.
+type Reference struct {
+	// Path - field path in the object, [*] selects all list items. Example: spec.backends[*].secretName.
+	Path string `json:"path"`
+	// Kind - kind of the referenced object, e.g. Secret.
+	Kind string `json:"kind"`
+}
+
+// ValueRule - lifts object field into values.yaml.
+type ValueRule struct {
+	// Path - field path in the object. List items are selected by index or by field value,
+	// keys containing dots are quoted. Example: spec.template.spec.containers[name=app].livenessProbe, data['app.conf'].
+	Path string `json:"path"`
+	// Value - dotted path in values.yaml. Defaults to object name followed by selected list items and field name,
+	// e.g. foo.app.livenessProbe for the path above and Deployment foo.
+	Value string `json:"value"`
+}
+
+// merge overrides options with options set in other.
+func (o ObjectOptions) merge(other ObjectOptions) ObjectOptions {
+	if other.Skip != nil {
+		o.Skip = other.Skip
+	}
+	if other.Filename != "" {
+		o.Filename = other.Filename
+	}
+	if other.Toggle != nil {
+		o.Toggle = other.Toggle
+	}
+	if other.ImagePullSecrets != nil {
+		o.ImagePullSecrets = other.ImagePullSecrets
+	}
+	if other.GenerateDefaults != nil {
+		o.GenerateDefaults = other.GenerateDefaults
+	}
+	if other.PreserveNs != nil {
+		o.PreserveNs = other.PreserveNs
+	}
+	if other.NamespaceMode != "" {
+		o.NamespaceMode = other.NamespaceMode
+	}
+	if other.EnvMode != "" {
+		o.EnvMode = other.EnvMode
+	}
+	if other.PodSpecs != nil {
+		o.PodSpecs = other.PodSpecs
+	}
+	if other.Lift != nil {
+		o.Lift = other.Lift
+	}
+	o.Values = append(o.Values[:len(o.Values):len(o.Values)], other.Values...)
+	o.References = append(o.References[:len(o.References):len(o.References)], other.References...)
+	return o
+}
+
+// Skipped returns true if objects must be excluded from the chart.
+func (o ObjectOptions) Skipped() bool {
+	return o.Skip != nil && *o.Skip
+}
+
+// Toggled returns true if templates of objects must be wrapped into enabled conditional.
+func (o ObjectOptions) Toggled() bool {
+	return o.Toggle != nil && *o.Toggle
+}
+
+// QualifiedKind returns kind prefixed with API group, e.g. "serving.knative.dev/Service".
+// Kinds from the core API group are returned as is.
+func QualifiedKind(group, kind string) string {
+	if group == "" {
+		return kind
+	}
+	return group + "/" + kind
+}
+
+// ObjectOptions returns options for the object with given kind and name. Kind can be qualified with API group,
+// see QualifiedKind. Options from Kinds are overridden by options from Objects in order of appearance.
+// Options for plain kind are overridden by options for qualified kind.
+func (c Config) ObjectOptions(kind, name string) ObjectOptions {
+	plain := kind[strings.LastIndex(kind, "/")+1:]
+	res := c.kindOptions(plain)
+	if plain != kind {
+		res = res.merge(c.kindOptions(kind))
+	}
+	res.Kind, res.Name = kind, name
+	for _, o := range c.Objects {
+		if o.Name == name && (o.Kind == "" || strings.EqualFold(o.Kind, plain) || strings.EqualFold(o.Kind, kind)) {
+			res = res.merge(o)
+		}
+	}
+	return res
+}
+
+// kindOptions returns options from Kinds for the given kind. Kinds are matched case-insensitively
+// the same way as config keys, see unknownKeys.
+func (c Config) kindOptions(kind string) ObjectOptions {
+	if o, ok := c.Kinds[kind]; ok {
+		return o
+	}
+	for k, o := range c.Kinds {
+		if strings.EqualFold(k, kind) {
+			return o
+		}
+	}
+	return ObjectOptions{}
+}
+
+// ForObject returns config with global options overridden by options of the object with given kind and name.
+func (c Config) ForObject(kind, name string) Config {
+	o := c.ObjectOptions(kind, name)
+	if o.ImagePullSecrets != nil {
+		c.ImagePullSecrets = *o.ImagePullSecrets
+	}
+	if o.GenerateDefaults != nil {
+		c.GenerateDefaults = *o.GenerateDefaults
+	}
+	if o.PreserveNs != nil {
+		c.PreserveNs = *o.PreserveNs
+		if c.PreserveNs || c.NamespaceMode == NamespacePreserve {
+			// object preserveNs option overrides global namespace mode
+			c.NamespaceMode = ""
+		}
+	}
+	if o.NamespaceMode != "" {
+		c.NamespaceMode = o.NamespaceMode
+	}
+	if o.EnvMode != "" {
+		c.EnvMode = o.EnvMode
+	}
+	return c
+}
+
+// LiftedFields returns top-level fields of the object with given kind and name moved into values.yaml
+// by the default processor.
+func (c Config) LiftedFields(kind, name string) []string {
+	o := c.ObjectOptions(kind, name)
+	if o.Lift != nil {
+		return o.Lift
+	}
+	if c.LiftSpec {
+		return []string{"spec"}
+	}
+	return nil
+}
+
+// validate checks value rules, pod spec paths, lifted fields, references, namespace and env modes.
+func (o ObjectOptions) validate() error {
+	if err := o.NamespaceMode.validate(); err != nil {
+		return err
+	}
+	if err := o.EnvMode.validate(); err != nil {
+		return err
+	}
+	for i, r := range o.Values {
+		if err := validatePath(r.Path, false); err != nil {
+			return fmt.Errorf("%w: values[%d]", err, i)
+		}
+	}
+	for i, p := range o.PodSpecs {
+		if err := validatePath(p, false); err != nil {
+			return fmt.Errorf("%w: podSpecs[%d]", err, i)
+		}
+	}
+	for i, r := range o.References {
+		if err := validatePath(r.Path, true); err != nil {
+			return fmt.Errorf("%w: references[%d]", err, i)
+		}
+		if r.Kind == "" {
+			return fmt.Errorf("references[%d]: kind is required", i)
+		}
+	}
+	for i, f := range o.Lift {
+		switch f {
+		case "", "apiVersion", "kind", "metadata":
+			return fmt.Errorf("lift[%d]: field %q can not be lifted", i, f)
+		}
+	}
+	return nil
+}
+
+func validatePath(path string, wildcard bool) error {
+	p, err := fieldpath.Parse(path)
+	if err != nil {
+		return err
+	}
+	if !wildcard && p.Wildcard() {
+		return fmt.Errorf("invalid path %q: [*] is not supported", path)
+	}
+	return nil
+}
+
+// LoadFile reads helmify config file into config. See Load.
+func (c *Config) LoadFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("%w: unable to read config file", err)
+	}
+	err = c.Load(data)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, file)
+	}
+	return nil
+}
+
+// Load reads helmify config from yaml. Only options presented in yaml are changed.
+// Unknown keys are reported by Validate.
+func (c *Config) Load(data []byte) error {
+	err := yaml.Unmarshal(data, c)
+	if err != nil {
+		return fmt.Errorf("%w: unable to parse config", err)
+	}
+	var raw interface{}
+	err = yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return fmt.Errorf("%w: unable to parse config", err)
+	}
+	c.unknownKeys = append(c.unknownKeys, unknownKeys(raw, reflect.TypeOf(c).Elem(), "")...)
+	sort.Strings(c.unknownKeys)
+	return nil
+}
+
+// unknownKeys returns paths of the keys from raw yaml not matching json fields of the given type.
+func unknownKeys(raw interface{}, t reflect.Type, path string) []string {
+	var res []string
+	switch t.Kind() {
+	case reflect.Ptr:
+		return unknownKeys(raw, t.Elem(), path)
+	case reflect.Slice:
+		items, _ := raw.([]interface{})
+		for i, item := range items {
+			res = append(res, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Map:
+		values, _ := raw.(map[string]interface{})
+		for key, value := range values {
+			res = append(res, unknownKeys(value, t.Elem(), joinPath(path, key))...)
+		}
+	case reflect.Struct:
+		values, _ := raw.(map[string]interface{})
+		// keys are matched case-insensitively as by yaml unmarshalling
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name != "" && name != "-" {
+				fields[strings.ToLower(name)] = t.Field(i).Type
+			}
+		}
+		for key, value := range values {
+			field, ok := fields[strings.ToLower(key)]
+			if !ok {
+				res = append(res, joinPath(path, key))
+				continue
+			}
+			res = append(res, unknownKeys(value, field, joinPath(path, key))...)
+		}
+	}
+	return res
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+chartName: mychart
+crdDir: true
+certManagerAsSubchart: true
+chart:
+  version: 1.0.0
+  maintainers:
+    - name: Jane
+kinds:
+  Secret:
+    skip: true
+  Deployment:
+    imagePullSecrets: true
+    values:
+      - path: spec.replicas
+objects:
+  - kind: Deployment
+    name: proxy
+    imagePullSecrets: false
+    filename: proxy.yaml
+    values:
+      - path: spec.template.spec.containers[name=proxy].livenessProbe
+        value: proxy.probe
+  - name: app-secret
+    skip: false
+`
+
+func TestConfig_Load(t *testing.T) {
+	c := Config{CertManagerVersion: "v1.12.2", CertManagerInstallCRD: true}
+	require.NoError(t, c.Load([]byte(testConfig)))
+	assert.NoError(t, c.Validate())
+	assert.Equal(t, "mychart", c.ChartName)
+	assert.True(t, c.Crd)
+	assert.True(t, c.CertManagerAsSubchart)
+	assert.True(t, c.CertManagerInstallCRD, "options not presented in file must be kept")
+	assert.Equal(t, "v1.12.2", c.CertManagerVersion)
+	assert.Equal(t, "1.0.0", c.Chart.Version)
+	assert.Equal(t, []Maintainer{{Name: "Jane"}}, c.Chart.Maintainers)
+
+	t.Run("unknown keys", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("chartName: a\ncrd: true\nchart:\n  vesion: 1\nkinds:\n  Secret:\n    skipp: true\nobjects:\n  - name: a\n    foo: bar\n")))
+		err := c.Validate()
+		assert.EqualError(t, err, "unknown config keys: chart.vesion, crd, kinds.Secret.skipp, objects[0].foo")
+	})
+	t.Run("keys case", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("ChartName: a\nkinds:\n  deployment:\n    Skip: true\nobjects:\n  - kind: secret\n    name: a\n    toggle: true\n")))
+		require.NoError(t, c.Validate())
+		assert.Equal(t, "a", c.ChartName)
+		assert.True(t, c.ObjectOptions("apps/Deployment", "web").Skipped())
+		assert.True(t, c.ObjectOptions("Secret", "a").Toggled())
+
+		c = Config{}
+		require.NoError(t, c.Load([]byte("kinds:\n  deployment:\n    skip: true\n  Deployment:\n    skip: false\n")))
+		assert.Error(t, c.Validate())
+	})
+	t.Run("object without name", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("objects:\n  - kind: Secret\n")))
+		assert.Error(t, c.Validate())
+	})
+	t.Run("invalid rule path", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("kinds:\n  ConfigMap:\n    values:\n      - path: data[\n")))
+		assert.Error(t, c.Validate())
+	})
+	t.Run("invalid pod spec path", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("kinds:\n  example.com/Worker:\n    podSpecs: ['spec[']\n")))
+		assert.Error(t, c.Validate())
+	})
+	t.Run("wildcard pod spec path", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("kinds:\n  example.com/Worker:\n    podSpecs: ['spec.jobs[*].spec']\n")))
+		assert.Error(t, c.Validate())
+	})
+	t.Run("references", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("kinds:\n  example.com/Backup:\n    references:\n      - path: spec.targets[*].secret\n        kind: Secret\n")))
+		assert.NoError(t, c.Validate())
+		c = Config{}
+		require.NoError(t, c.Load([]byte("kinds:\n  example.com/Backup:\n    references:\n      - path: spec.secret\n")))
+		assert.Error(t, c.Validate(), "kind is required")
+	})
+	t.Run("invalid lifted field", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("objects:\n  - name: app\n    lift: [metadata]\n")))
+		assert.Error(t, c.Validate())
+	})
+	t.Run("invalid namespace mode", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("namespaceMode: keep\n")))
+		assert.Error(t, c.Validate())
+	})
+	t.Run("invalid env mode", func(t *testing.T) {
+		c := Config{}
+		require.NoError(t, c.Load([]byte("objects:\n  - name: app\n    envMode: array\n")))
+		assert.Error(t, c.Validate())
+	})
+	t.Run("invalid yaml", func(t *testing.T) {
+		c := Config{}
+		assert.Error(t, c.Load([]byte("chartName: [")))
+	})
+}
+
+func TestConfig_ObjectOptions(t *testing.T) {
+	c := Config{}
+	require.NoError(t, c.Load([]byte(testConfig)))
+
+	assert.True(t, c.ObjectOptions("Secret", "other").Skipped())
+	assert.False(t, c.ObjectOptions("Secret", "app-secret").Skipped())
+	assert.False(t, c.ObjectOptions("ConfigMap", "app-secret").Skipped())
+
+	assert.True(t, c.ForObject("Deployment", "app").ImagePullSecrets)
+	assert.False(t, c.ForObject("Deployment", "proxy").ImagePullSecrets)
+	assert.False(t, c.ForObject("StatefulSet", "app").ImagePullSecrets)
+	assert.Equal(t, "proxy.yaml", c.ObjectOptions("Deployment", "proxy").Filename)
+	assert.Equal(t, "", c.ObjectOptions("Deployment", "app").Filename)
+	assert.Equal(t, []ValueRule{{Path: "spec.replicas"}}, c.ObjectOptions("Deployment", "app").Values)
+	assert.Equal(t, []ValueRule{
+		{Path: "spec.replicas"},
+		{Path: "spec.template.spec.containers[name=proxy].livenessProbe", Value: "proxy.probe"},
+	}, c.ObjectOptions("Deployment", "proxy").Values)
+	assert.Len(t, c.Kinds["Deployment"].Values, 1, "kind options must not be modified")
+}
+
+func TestConfig_ObjectOptions_qualifiedKind(t *testing.T) {
+	c := Config{}
+	require.NoError(t, c.Load([]byte(`kinds:
+  Service:
+    filename: services.yaml
+  serving.knative.dev/Service:
+    podSpecs: [spec.template.spec]
+objects:
+  - kind: serving.knative.dev/Service
+    name: web
+    podSpecs: []
+`)))
+	require.NoError(t, c.Validate())
+
+	assert.Equal(t, "serving.knative.dev/Service", QualifiedKind("serving.knative.dev", "Service"))
+	assert.Equal(t, "Service", QualifiedKind("", "Service"))
+
+	assert.Nil(t, c.ObjectOptions("Service", "api").PodSpecs)
+	opts := c.ObjectOptions("serving.knative.dev/Service", "api")
+	assert.Equal(t, []string{"spec.template.spec"}, opts.PodSpecs)
+	assert.Equal(t, "services.yaml", opts.Filename, "options for plain kind must be inherited")
+	assert.Equal(t, []string{}, c.ObjectOptions("serving.knative.dev/Service", "web").PodSpecs)
+	assert.Nil(t, c.ObjectOptions("Service", "web").PodSpecs)
+
+	c = Config{
+		Kinds:   map[string]ObjectOptions{"example.com/Backup": {References: []Reference{{Path: "spec.a", Kind: "Secret"}}}},
+		Objects: []ObjectOptions{{Name: "db", References: []Reference{{Path: "spec.b", Kind: "ConfigMap"}}}},
+	}
+	assert.Equal(t, []Reference{{Path: "spec.a", Kind: "Secret"}, {Path: "spec.b", Kind: "ConfigMap"}},
+		c.ObjectOptions("example.com/Backup", "db").References, "references must be combined")
+}
+
+func TestConfig_LiftedFields(t *testing.T) {
+	c := Config{Kinds: map[string]ObjectOptions{"Settings": {Lift: []string{"data"}}, "Worker": {Lift: []string{}}}}
+	assert.Nil(t, c.LiftedFields("Other", "app"))
+	c.LiftSpec = true
+	assert.Equal(t, []string{"spec"}, c.LiftedFields("Other", "app"))
+	assert.Equal(t, []string{"data"}, c.LiftedFields("example.com/Settings", "app"))
+	assert.Empty(t, c.LiftedFields("Worker", "app"))
+}
+
+func TestConfig_Namespaces(t *testing.T) {
+	assert.Equal(t, NamespaceOmit, Config{}.Namespaces())
+	assert.Equal(t, NamespacePreserve, Config{PreserveNs: true}.Namespaces())
+	assert.Equal(t, NamespaceValues, Config{PreserveNs: true, NamespaceMode: NamespaceValues}.Namespaces())
+
+	yes, no := true, false
+	c := Config{NamespaceMode: NamespaceRelease, Objects: []ObjectOptions{
+		{Name: "preserved", PreserveNs: &yes},
+		{Name: "not-preserved", PreserveNs: &no},
+		{Name: "values", NamespaceMode: NamespaceValues},
+	}}
+	assert.Equal(t, NamespaceRelease, c.ForObject("Service", "other").Namespaces())
+	assert.Equal(t, NamespacePreserve, c.ForObject("Service", "preserved").Namespaces())
+	assert.Equal(t, NamespaceRelease, c.ForObject("Service", "not-preserved").Namespaces())
+	assert.Equal(t, NamespaceValues, c.ForObject("Service", "values").Namespaces())
+	c.NamespaceMode = NamespacePreserve
+	assert.Equal(t, NamespaceOmit, c.ForObject("Service", "not-preserved").Namespaces())
+}
+
+func TestConfig_Env(t *testing.T) {
+	assert.Equal(t, EnvMap, Config{}.Env())
+	c := Config{EnvMode: EnvList, Objects: []ObjectOptions{{Name: "map", EnvMode: EnvMap}}}
+	assert.Equal(t, EnvList, c.ForObject("Deployment", "other").Env())
+	assert.Equal(t, EnvMap, c.ForObject("Deployment", "map").Env())
+}
@@ -0,0 +1,184 @@
+// Package fieldpath parses and resolves field paths of k8s objects like spec.containers[name=app].image.
+package fieldpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Segment - single path element: map key optionally followed by list item selector.
+type Segment struct {
+	// Key - map key.
+	Key string
+	// Index - list item index. -1 if item is selected by field or not selected.
+	Index int
+	// Field and FieldValue select list item by its field value, e.g. [name=app].
+	Field, FieldValue string
+	// All selects every list item: [*]. Supported only by Walk.
+	All bool
+}
+
+// Selector returns true if segment selects list item.
+func (s Segment) Selector() bool {
+	return s.Index >= 0 || s.Field != "" || s.All
+}
+
+// Path - parsed field path.
+type Path []Segment
+
+// Parse parses field path. Keys are separated by dots, list items are selected by index [0] or by field value
+// [name=app], keys containing dots or brackets are quoted: data['app.conf']. [*] selects all list items.
+func Parse(path string) (Path, error) {
+	var res Path
+	rest := path
+	for rest != "" {
+		seg := Segment{Index: -1}
+		if strings.HasPrefix(rest, "['") {
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed quote", path)
+			}
+			seg.Key, rest = rest[2:end], rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			seg.Key, rest = rest[:end], rest[end:]
+		}
+		if seg.Key == "" {
+			return nil, fmt.Errorf("invalid path %q: empty key", path)
+		}
+		if strings.HasPrefix(rest, "[") && !strings.HasPrefix(rest, "['") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed bracket", path)
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+			if selector == "*" {
+				seg.All = true
+			} else if field, value, ok := strings.Cut(selector, "="); ok {
+				if field == "" {
+					return nil, fmt.Errorf("invalid path %q: empty selector field", path)
+				}
+				seg.Field, seg.FieldValue = field, value
+			} else {
+				idx, err := strconv.Atoi(selector)
+				if err != nil || idx < 0 {
+					return nil, fmt.Errorf("invalid path %q: selector must be index or field=value", path)
+				}
+				seg.Index = idx
+			}
+		}
+		res = append(res, seg)
+		if rest == "" {
+			break
+		}
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" {
+				return nil, fmt.Errorf("invalid path %q: trailing dot", path)
+			}
+			continue
+		}
+		if !strings.HasPrefix(rest, "['") {
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", path, rest)
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("invalid path %q: empty path", path)
+	}
+	return res, nil
+}
+
+// Wildcard returns true if path selects all items of some list.
+func (p Path) Wildcard() bool {
+	for _, seg := range p {
+		if seg.All {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns value of the field with given path from unstructured object content.
+// Paths with wildcards are never found, see Walk.
+func (p Path) Get(obj map[string]interface{}) (interface{}, bool) {
+	var cur interface{} = obj
+	for _, seg := range p {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[seg.Key]
+		if !ok {
+			return nil, false
+		}
+		if !seg.Selector() {
+			continue
+		}
+		items, ok := cur.([]interface{})
+		if !ok || seg.All {
+			return nil, false
+		}
+		idx := seg.Index
+		if seg.Field != "" {
+			idx = -1
+			for i, item := range items {
+				if seg.Match(item) {
+					idx = i
+					break
+				}
+			}
+		}
+		if idx < 0 || idx >= len(items) {
+			return nil, false
+		}
+		cur = items[idx]
+	}
+	return cur, true
+}
+
+// Match returns true if list item is selected by segment field selector.
+func (s Segment) Match(item interface{}) bool {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	value, ok := m[s.Field]
+	return ok && fmt.Sprint(value) == s.FieldValue
+}
+
+// Walk calls fn for every map containing the last path key, following all list items selected by the path.
+// fn may change the value of the key in place.
+func (p Path) Walk(obj map[string]interface{}, fn func(parent map[string]interface{}, key string)) {
+	if len(p) == 0 {
+		return
+	}
+	seg := p[0]
+	value, ok := obj[seg.Key]
+	if !ok {
+		return
+	}
+	if !seg.Selector() {
+		if len(p) == 1 {
+			fn(obj, seg.Key)
+			return
+		}
+		if m, ok := value.(map[string]interface{}); ok {
+			p[1:].Walk(m, fn)
+		}
+		return
+	}
+	items, _ := value.([]interface{})
+	for i, item := range items {
+		if !seg.All && i != seg.Index && !(seg.Field != "" && seg.Match(item)) {
+			continue
+		}
+		if m, ok := item.(map[string]interface{}); ok && len(p) > 1 {
+			p[1:].Walk(m, fn)
+		}
+	}
+}
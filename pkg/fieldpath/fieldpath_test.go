@@ -0,0 +1,101 @@
+package fieldpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse("spec.containers[name=app].ports[0].containerPort")
+	require.NoError(t, err)
+	assert.Equal(t, Path{
+		{Key: "spec", Index: -1},
+		{Key: "containers", Index: -1, Field: "name", FieldValue: "app"},
+		{Key: "ports", Index: 0},
+		{Key: "containerPort", Index: -1},
+	}, p)
+
+	p, err = Parse("data['app.conf']")
+	require.NoError(t, err)
+	assert.Equal(t, Path{{Key: "data", Index: -1}, {Key: "app.conf", Index: -1}}, p)
+
+	for _, invalid := range []string{"", "spec.", "spec..a", "a[", "a[x]", "a[=b]", "a['b", "a[0]b", "a[-1]"} {
+		_, err = Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPath_Get(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "proxy"},
+				map[string]interface{}{"name": "app", "ports": []interface{}{
+					map[string]interface{}{"containerPort": int64(80)},
+				}},
+			},
+		},
+		"data": map[string]interface{}{"app.conf": "a=b"},
+	}
+	for path, want := range map[string]interface{}{
+		"spec.containers[name=app].ports[0].containerPort": int64(80),
+		"spec.containers[0].name":                          "proxy",
+		"data['app.conf']":                                 "a=b",
+	} {
+		p, err := Parse(path)
+		require.NoError(t, err)
+		got, found := p.Get(obj)
+		assert.True(t, found, path)
+		assert.Equal(t, want, got, path)
+	}
+	for _, path := range []string{"spec.containers[name=db]", "spec.containers[2]", "spec.missing", "data.x.y", "spec[0]"} {
+		p, err := Parse(path)
+		require.NoError(t, err)
+		_, found := p.Get(obj)
+		assert.False(t, found, path)
+	}
+}
+
+func TestPath_Walk(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "proxy", "envFrom": []interface{}{
+					map[string]interface{}{"secretRef": map[string]interface{}{"name": "a"}},
+				}},
+				map[string]interface{}{"name": "app", "envFrom": []interface{}{
+					map[string]interface{}{"configMapRef": map[string]interface{}{"name": "b"}},
+					map[string]interface{}{"secretRef": map[string]interface{}{"name": "c"}},
+				}},
+			},
+		},
+	}
+	p, err := Parse("spec.containers[*].envFrom[*].secretRef.name")
+	require.NoError(t, err)
+	assert.True(t, p.Wildcard())
+	_, found := p.Get(obj)
+	assert.False(t, found)
+
+	var names []interface{}
+	p.Walk(obj, func(parent map[string]interface{}, key string) {
+		names = append(names, parent[key])
+		parent[key] = "x"
+	})
+	assert.Equal(t, []interface{}{"a", "c"}, names)
+	got, _ := mustParse(t, "spec.containers[name=app].envFrom[1].secretRef.name").Get(obj)
+	assert.Equal(t, "x", got)
+
+	names = nil
+	mustParse(t, "spec.containers[name=app].name").Walk(obj, func(parent map[string]interface{}, key string) {
+		names = append(names, parent[key])
+	})
+	assert.Equal(t, []interface{}{"app"}, names)
+}
+
+func mustParse(t *testing.T, path string) Path {
+	p, err := Parse(path)
+	require.NoError(t, err)
+	return p
+}
@@ -1,12 +1,13 @@
 package helm
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/EdgeGamingGG/helmify/pkg/cluster"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 
 	"github.com/sirupsen/logrus"
@@ -16,10 +17,17 @@ import (
 
 // NewOutput creates interface to dump processed input to filesystem in Helm chart format.
 func NewOutput() helmify.Output {
-	return &output{}
+	return &output{fs: osFS{}}
 }
 
-type output struct{}
+// NewFSOutput creates interface to dump processed input to the given file system in Helm chart format.
+func NewFSOutput(fs FS) helmify.Output {
+	return &output{fs: fs}
+}
+
+type output struct {
+	fs FS
+}
 
 // Create a helm chart in the current directory:
 // chartName/
@@ -27,108 +35,151 @@ type output struct{}
 //	├── .helmignore   	# Contains patterns to ignore when packaging Helm charts.
 //	├── Chart.yaml    	# Information about your chart
 //	├── values.yaml   	# The default values for your templates
+//	├── values.schema.json	# Optional JSON schema for values.yaml
 //	└── templates/    	# The template files
 //	    └── _helpers.tp   # Helm default template partials
 //
 // Overwrites existing values.yaml and templates in templates dir on every run.
-func (o output) Create(chartDir, chartName string, crd bool, certManagerAsSubchart bool, certManagerVersion string, certManagerInstallCRD bool, templates []helmify.Template, filenames []string) error {
-	err := initChartDir(chartDir, chartName, crd, certManagerAsSubchart, certManagerVersion)
-	if err != nil {
-		return err
-	}
+// With conf.MergeValues values.yaml is merged with generated values instead, see mergeValues for details.
+func (o output) Create(conf config.Config, templates []helmify.Template, filenames []string) error {
+	_, err := o.create(conf, templates, filenames)
+	return err
+}
+
+// create writes chart files and returns resulting values.yaml content.
+func (o output) create(conf config.Config, templates []helmify.Template, filenames []string) (helmify.Values, error) {
 	// group templates into files
 	files := map[string][]helmify.Template{}
 	values := helmify.Values{}
-	values[cluster.DomainKey] = cluster.DefaultDomain
+	schema := helmify.Schema{}
 	for i, template := range templates {
 		file := files[filenames[i]]
 		file = append(file, template)
 		files[filenames[i]] = file
-		err = values.Merge(template.Values())
+		err := values.Merge(template.Values())
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if provider, ok := template.(helmify.SchemaProvider); ok {
+			schema.Merge(provider.Schema())
 		}
 	}
-	cDir := filepath.Join(chartDir, chartName)
+	err := o.initChartDir(conf, values)
+	if err != nil {
+		return nil, err
+	}
+	cDir := filepath.Join(conf.ChartDir, conf.ChartName)
 	for filename, tpls := range files {
-		err = overwriteTemplateFile(filename, cDir, crd, tpls)
+		err = o.overwriteTemplateFile(filename, cDir, conf.Crd, tpls)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	err = overwriteValuesFile(cDir, values, certManagerAsSubchart, certManagerInstallCRD)
+	values, err = o.overwriteValuesFile(cDir, values, conf.CertManagerAsSubchart, conf.CertManagerInstallCRD, conf.MergeValues)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	if conf.ValuesSchema {
+		if conf.CertManagerAsSubchart {
+			// cert-manager subchart values are validated by the subchart
+			schema.Add("additionalProperties", true, "certmanager")
+		}
+		templatesStr, err := o.templatesContent(cDir, templates)
+		if err != nil {
+			return nil, err
+		}
+		err = o.overwriteValuesSchemaFile(cDir, values, schema, templatesStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
 }
 
-func overwriteTemplateFile(filename, chartDir string, crd bool, templates []helmify.Template) error {
+// templatesContent returns content of all templates including _helpers.tpl to look up values references.
+func (o output) templatesContent(chartDir string, templates []helmify.Template) (string, error) {
+	buf := bytes.Buffer{}
+	helpers, err := o.fs.ReadFile(filepath.Join(chartDir, "templates", "_helpers.tpl"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: unable to read _helpers.tpl", err)
+	}
+	buf.Write(helpers)
+	for _, t := range templates {
+		buf.WriteString("\n")
+		err = t.Write(&buf)
+		if err != nil {
+			return "", fmt.Errorf("%w: unable to write template", err)
+		}
+	}
+	return buf.String(), nil
+}
+
+func (o output) overwriteTemplateFile(filename, chartDir string, crd bool, templates []helmify.Template) error {
 	// pull in crd-dir setting and siphon crds into folder
 	var subdir string
 	if strings.Contains(filename, "crd") && crd {
 		subdir = "crds"
 		// create "crds" if not exists
-		if _, err := os.Stat(filepath.Join(chartDir, "crds")); os.IsNotExist(err) {
-			err = os.MkdirAll(filepath.Join(chartDir, "crds"), 0750)
-			if err != nil {
-				return fmt.Errorf("%w: unable create crds dir", err)
-			}
+		err := o.fs.MkdirAll(filepath.Join(chartDir, "crds"))
+		if err != nil {
+			return fmt.Errorf("%w: unable create crds dir", err)
 		}
 	} else {
 		subdir = "templates"
 	}
 	file := filepath.Join(chartDir, subdir, filename)
-	f, err := os.OpenFile(file, os.O_APPEND|os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	if err != nil {
-		return fmt.Errorf("%w: unable to open %s", err, file)
-	}
-	defer f.Close()
+	f := bytes.Buffer{}
 	for i, t := range templates {
 		logrus.WithField("file", file).Debug("writing a template into")
-		err = t.Write(f)
+		err := t.Write(&f)
 		if err != nil {
 			return fmt.Errorf("%w: unable to write into %s", err, file)
 		}
 		if i != len(templates)-1 {
-			_, err = f.Write([]byte("\n---\n"))
-			if err != nil {
-				return fmt.Errorf("%w: unable to write into %s", err, file)
-			}
+			f.WriteString("\n---\n")
 		}
 	}
 	if len(templates) != 0 {
-		_, err = f.Write([]byte("\n"))
-		if err != nil {
-			return fmt.Errorf("%w: unable to write newline into %s", err, file)
-		}
+		f.WriteString("\n")
+	}
+	err := o.fs.WriteFile(file, f.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("%w: unable to write %s", err, file)
 	}
 	logrus.WithField("file", file).Info("overwritten")
 	return nil
 }
 
-func overwriteValuesFile(chartDir string, values helmify.Values, certManagerAsSubchart bool, certManagerInstallCRD bool) error {
+// overwriteValuesFile writes values.yaml and returns its content.
+func (o output) overwriteValuesFile(chartDir string, values helmify.Values, certManagerAsSubchart bool, certManagerInstallCRD bool, merge bool) (helmify.Values, error) {
 	if certManagerAsSubchart {
 		_, err := values.Add(certManagerInstallCRD, "certmanager", "installCRDs")
 		if err != nil {
-			return fmt.Errorf("%w: unable to add cert-manager.installCRDs", err)
+			return nil, fmt.Errorf("%w: unable to add cert-manager.installCRDs", err)
 		}
 
 		_, err = values.Add(true, "certmanager", "enabled")
 		if err != nil {
-			return fmt.Errorf("%w: unable to add cert-manager.enabled", err)
+			return nil, fmt.Errorf("%w: unable to add cert-manager.enabled", err)
+		}
+	}
+	if merge {
+		var err error
+		values, err = o.mergeValuesFile(chartDir, values)
+		if err != nil {
+			return nil, err
 		}
 	}
 	res, err := yaml.Marshal(values)
 	if err != nil {
-		return fmt.Errorf("%w: unable to write marshal values.yaml", err)
+		return nil, fmt.Errorf("%w: unable to write marshal values.yaml", err)
 	}
 
 	file := filepath.Join(chartDir, "values.yaml")
-	err = os.WriteFile(file, res, 0600)
+	err = o.fs.WriteFile(file, res, 0600)
 	if err != nil {
-		return fmt.Errorf("%w: unable to write values.yaml", err)
+		return nil, fmt.Errorf("%w: unable to write values.yaml", err)
 	}
 	logrus.WithField("file", file).Info("overwritten")
-	return nil
+	return values, nil
 }
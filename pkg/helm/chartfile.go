@@ -0,0 +1,124 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"gopkg.in/yaml.v3"
+)
+
+// updateChartFile sets non-empty metadata fields in Chart.yaml content.
+// Comments, order and fields not managed by helmify (e.g. dependencies) are preserved.
+func updateChartFile(content []byte, meta config.ChartMeta) ([]byte, error) {
+	doc := yaml.Node{}
+	err := yaml.Unmarshal(content, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse Chart.yaml", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("unable to parse Chart.yaml: mapping expected")
+	}
+	chart := doc.Content[0]
+
+	setScalar(chart, "version", meta.Version, 0)
+	// it is recommended to quote appVersion
+	setScalar(chart, "appVersion", meta.AppVersion, yaml.DoubleQuotedStyle)
+	setScalar(chart, "description", meta.Description, 0)
+	setScalar(chart, "home", meta.Home, 0)
+	setScalar(chart, "icon", meta.Icon, 0)
+	setScalar(chart, "kubeVersion", meta.KubeVersion, yaml.DoubleQuotedStyle)
+	if len(meta.Keywords) != 0 {
+		setNode(chart, "keywords", stringsNode(meta.Keywords))
+	}
+	if len(meta.Sources) != 0 {
+		setNode(chart, "sources", stringsNode(meta.Sources))
+	}
+	if len(meta.Maintainers) != 0 {
+		maintainers := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, m := range meta.Maintainers {
+			maintainer := &yaml.Node{Kind: yaml.MappingNode}
+			setScalar(maintainer, "name", m.Name, 0)
+			setScalar(maintainer, "email", m.Email, 0)
+			setScalar(maintainer, "url", m.URL, 0)
+			maintainers.Content = append(maintainers.Content, maintainer)
+		}
+		setNode(chart, "maintainers", maintainers)
+	}
+
+	buf := bytes.Buffer{}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	err = enc.Encode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to marshal Chart.yaml", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func setScalar(mapping *yaml.Node, key, value string, style yaml.Style) {
+	if value == "" {
+		return
+	}
+	setNode(mapping, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: style})
+}
+
+// setNode replaces value of the given key in mapping node or appends key if not exists.
+func setNode(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i < len(mapping.Content)-1; i += 2 {
+		if mapping.Content[i].Value == key {
+			value.HeadComment = mapping.Content[i+1].HeadComment
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+func stringsNode(values []string) *yaml.Node {
+	res := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, v := range values {
+		res.Content = append(res.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v})
+	}
+	return res
+}
+
+// appVersionFromValues returns the most common container image tag from values or empty string if there are no images.
+func appVersionFromValues(values helmify.Values) string {
+	tags := map[string]int{}
+	countImageTags(values, tags)
+	var res string
+	var names []string
+	for tag := range tags {
+		names = append(names, tag)
+	}
+	// sort to get the same result for equally common tags
+	sort.Strings(names)
+	for _, tag := range names {
+		if tags[tag] > tags[res] {
+			res = tag
+		}
+	}
+	return res
+}
+
+func countImageTags(values map[string]interface{}, tags map[string]int) {
+	for key, value := range values {
+		valueMap, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if key == "image" {
+			if _, hasRepo := valueMap["repository"]; hasRepo {
+				if tag, ok := valueMap["tag"].(string); ok && tag != "" {
+					tags[tag]++
+				}
+				continue
+			}
+		}
+		countImageTags(valueMap, tags)
+	}
+}
@@ -0,0 +1,73 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_updateChartFile(t *testing.T) {
+	t.Run("new chart", func(t *testing.T) {
+		res, err := updateChartFile(chartYAML("app", false, ""), config.ChartMeta{
+			Version:     "1.2.3",
+			AppVersion:  "v2",
+			Keywords:    []string{"a", "b"},
+			Maintainers: []config.Maintainer{{Name: "Jane", Email: "jane@example.com"}},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, string(res), "# Versions are expected to follow Semantic Versioning (https://semver.org/)\nversion: 1.2.3\n")
+		assert.Contains(t, string(res), "appVersion: \"v2\"\n")
+		assert.Contains(t, string(res), "keywords:\n  - a\n  - b\n")
+		assert.Contains(t, string(res), "maintainers:\n  - name: Jane\n    email: jane@example.com\n")
+	})
+	t.Run("existing chart", func(t *testing.T) {
+		existing := `apiVersion: v2
+name: app
+version: 0.1.0 # my comment
+appVersion: "1.0"
+custom: value
+keywords:
+  - old
+dependencies:
+  - name: redis
+    version: 1.0.0
+`
+		res, err := updateChartFile([]byte(existing), config.ChartMeta{Version: "0.2.0", Keywords: []string{"new"}, Description: "desc"})
+		require.NoError(t, err)
+		assert.Equal(t, `apiVersion: v2
+name: app
+version: 0.2.0 # my comment
+appVersion: "1.0"
+custom: value
+keywords:
+  - new
+dependencies:
+  - name: redis
+    version: 1.0.0
+description: desc
+`, string(res))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := updateChartFile([]byte("- a"), config.ChartMeta{Version: "1"})
+		assert.Error(t, err)
+	})
+}
+
+func Test_appVersionFromValues(t *testing.T) {
+	values := helmify.Values{
+		"a": map[string]interface{}{"app": map[string]interface{}{
+			"image": map[string]interface{}{"repository": "app", "tag": "v1"},
+		}},
+		"b": map[string]interface{}{"app": map[string]interface{}{
+			"image": map[string]interface{}{"repository": "app", "tag": "v1"},
+		}},
+		"c": map[string]interface{}{"proxy": map[string]interface{}{
+			"image": map[string]interface{}{"repository": "proxy", "tag": "v0"},
+		}},
+	}
+	assert.Equal(t, "v1", appVersionFromValues(values))
+	assert.Equal(t, "", appVersionFromValues(helmify.Values{}))
+}
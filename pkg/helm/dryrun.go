@@ -0,0 +1,79 @@
+package helm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ErrChartChanged - returned by dry run output when generated chart differs from the chart on filesystem.
+var ErrChartChanged = errors.New("generated chart differs from existing chart")
+
+// NewDryRunOutput creates interface to compare processed input with the Helm chart on filesystem without writing it.
+// Prints unified diff of changed chart files into writer and returns ErrChartChanged if there are any differences.
+func NewDryRunOutput(writer io.Writer) helmify.Output {
+	return &dryRunOutput{writer: writer}
+}
+
+type dryRunOutput struct {
+	writer io.Writer
+}
+
+// Create a helm chart in memory and print its difference with the chart on filesystem.
+func (o dryRunOutput) Create(conf config.Config, templates []helmify.Template, filenames []string) error {
+	mem := newMemFS(osFS{})
+	err := output{fs: mem}.Create(conf, templates, filenames)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(mem.files))
+	for name := range mem.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	changed := false
+	for _, name := range names {
+		fromFile := name
+		existing, err := os.ReadFile(name)
+		if os.IsNotExist(err) {
+			fromFile = "/dev/null"
+		} else if err != nil {
+			return fmt.Errorf("%w: unable to read %s", err, name)
+		}
+		if bytes.Equal(existing, mem.files[name]) {
+			continue
+		}
+		changed = true
+		err = difflib.WriteUnifiedDiff(o.writer, difflib.UnifiedDiff{
+			A:        splitLines(existing),
+			B:        splitLines(mem.files[name]),
+			FromFile: fromFile,
+			ToFile:   name,
+			Context:  3,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: unable to write diff of %s", err, name)
+		}
+	}
+	if changed {
+		return ErrChartChanged
+	}
+	return nil
+}
+
+// splitLines splits content into lines keeping line endings.
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
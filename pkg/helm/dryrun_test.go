@@ -0,0 +1,66 @@
+package helm
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTemplate struct {
+	data   string
+	values helmify.Values
+}
+
+func (t testTemplate) Filename() string {
+	return "test.yaml"
+}
+
+func (t testTemplate) Values() helmify.Values {
+	return t.values
+}
+
+func (t testTemplate) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte(t.data))
+	return err
+}
+
+func Test_dryRunOutput_Create(t *testing.T) {
+	dir := t.TempDir()
+	create := func(out helmify.Output, tpl testTemplate) error {
+		return out.Create(config.Config{ChartDir: dir, ChartName: "chart"}, []helmify.Template{tpl}, []string{tpl.Filename()})
+	}
+	tpl := testTemplate{data: "replicas: {{ .Values.replicas }}", values: helmify.Values{"replicas": int64(1)}}
+
+	t.Run("new chart not written", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		err := create(NewDryRunOutput(&buf), tpl)
+		assert.ErrorIs(t, err, ErrChartChanged)
+		assert.Contains(t, buf.String(), "--- /dev/null\n+++ "+filepath.Join(dir, "chart", "Chart.yaml"))
+		_, err = os.Stat(filepath.Join(dir, "chart"))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("no diff for up to date chart", func(t *testing.T) {
+		require.NoError(t, create(NewOutput(), tpl))
+		buf := bytes.Buffer{}
+		assert.NoError(t, create(NewDryRunOutput(&buf), tpl))
+		assert.Empty(t, buf.String())
+	})
+	t.Run("diff of changed files", func(t *testing.T) {
+		changed := testTemplate{data: tpl.data, values: helmify.Values{"replicas": int64(2)}}
+		buf := bytes.Buffer{}
+		err := create(NewDryRunOutput(&buf), changed)
+		assert.ErrorIs(t, err, ErrChartChanged)
+		assert.Equal(t, "--- "+filepath.Join(dir, "chart", "values.yaml")+"\n"+
+			"+++ "+filepath.Join(dir, "chart", "values.yaml")+"\n"+
+			"@@ -1 +1 @@\n"+
+			"-replicas: 1\n"+
+			"+replicas: 2\n", buf.String())
+	})
+}
@@ -0,0 +1,61 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FS - file system used by output to read and write chart files.
+type FS interface {
+	// MkdirAll creates a directory with all parents.
+	MkdirAll(path string) error
+	// ReadFile returns file content. Returns error satisfying os.IsNotExist if file does not exist.
+	ReadFile(name string) ([]byte, error)
+	// WriteFile writes file content. Creates file if not exists.
+	WriteFile(name string, data []byte, perm os.FileMode) error
+}
+
+// osFS - FS backed by the OS file system.
+type osFS struct{}
+
+func (osFS) MkdirAll(path string) error {
+	return os.MkdirAll(path, 0750)
+}
+
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (osFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// memFS - in-memory FS. Files not written in memory are read from the base FS if presented,
+// so existing chart files like Chart.yaml or values.yaml are taken into account.
+type memFS struct {
+	base  FS
+	files map[string][]byte
+}
+
+func newMemFS(base FS) *memFS {
+	return &memFS{base: base, files: map[string][]byte{}}
+}
+
+func (m *memFS) MkdirAll(string) error {
+	return nil
+}
+
+func (m *memFS) ReadFile(name string) ([]byte, error) {
+	if content, ok := m.files[filepath.Clean(name)]; ok {
+		return content, nil
+	}
+	if m.base == nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return m.base.ReadFile(name)
+}
+
+func (m *memFS) WriteFile(name string, data []byte, _ os.FileMode) error {
+	m.files[filepath.Clean(name)] = data
+	return nil
+}
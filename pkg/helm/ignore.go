@@ -0,0 +1,70 @@
+package helm
+
+import (
+	"path"
+	"strings"
+)
+
+// ignoreRules - patterns of .helmignore file. Supports the syntax of 'helm package': shell glob patterns,
+// comments, negation prefixed with '!' and directory patterns ending with '/'. Patterns containing '/' are matched
+// against the path relative to the chart dir, other patterns against the file or directory name.
+type ignoreRules []ignorePattern
+
+type ignorePattern struct {
+	pattern string
+	negate  bool
+	mustDir bool
+}
+
+// parseIgnore parses .helmignore content.
+func parseIgnore(content string) ignoreRules {
+	var res ignoreRules
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || line == "!" {
+			continue
+		}
+		p := ignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.mustDir = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		p.pattern = strings.TrimPrefix(line, "/")
+		res = append(res, p)
+	}
+	return res
+}
+
+// Ignore returns true if the file with slash separated path relative to the chart dir is ignored
+// directly or by one of its parent directories.
+func (r ignoreRules) Ignore(file string) bool {
+	parts := strings.Split(file, "/")
+	for i := range parts {
+		if r.ignore(path.Join(parts[:i+1]...), i < len(parts)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignore applies patterns to a single path. The last matching pattern wins.
+func (r ignoreRules) ignore(name string, isDir bool) bool {
+	res := false
+	for _, p := range r {
+		if p.mustDir && !isDir {
+			continue
+		}
+		target := name
+		if !strings.Contains(p.pattern, "/") {
+			target = path.Base(name)
+		}
+		if ok, _ := path.Match(p.pattern, target); ok {
+			res = !p.negate
+		}
+	}
+	return res
+}
@@ -0,0 +1,34 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ignoreRules_Ignore(t *testing.T) {
+	rules := parseIgnore(`# comment
+*.bak
+.vscode/
+/docs/*.md
+!docs/README.md
+templates/tests/
+`)
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{file: "values.yaml", want: false},
+		{file: "notes.bak", want: true},
+		{file: "templates/old.yaml.bak", want: true},
+		{file: ".vscode/settings.json", want: true},
+		{file: ".vscode", want: false},
+		{file: "docs/usage.md", want: true},
+		{file: "docs/README.md", want: false},
+		{file: "templates/tests/test-connection.yaml", want: true},
+		{file: "templates/deployment.yaml", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, rules.Ignore(tt.file), tt.file)
+	}
+}
@@ -1,12 +1,16 @@
 package helm
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 	"github.com/sirupsen/logrus"
 )
 
@@ -33,6 +37,9 @@ const helmIgnore = `# Patterns to ignore when building packages.
 .idea/
 *.tmproj
 .vscode/
+# Helmify config and values generated on the previous run
+.helmify.yaml
+.helmify.values.yaml
 `
 
 const defaultHelpers = `{{/*
@@ -87,6 +94,44 @@ app.kubernetes.io/name: {{ include "<CHARTNAME>.name" . }}
 app.kubernetes.io/instance: {{ .Release.Name }}
 {{- end }}
 
+{{/*
+Adds selector labels to label selectors of affinity terms or topology spread constraints selecting the workload
+own pods. Expects dict with "value" - affinity or topology spread constraints, "podLabels" - labels of the pod
+template and "context" - chart context.
+*/}}
+{{- define "<CHARTNAME>.scopeSelectors" -}}
+{{- $value := deepCopy .value }}
+{{- $podLabels := .podLabels }}
+{{- $selectorLabels := include "<CHARTNAME>.selectorLabels" .context | fromYaml }}
+{{- $selectors := list }}
+{{- if kindIs "slice" $value }}
+{{- range $value }}
+{{- $selectors = append $selectors (dig "labelSelector" dict .) }}
+{{- end }}
+{{- else }}
+{{- range $affinity := list "podAffinity" "podAntiAffinity" }}
+{{- range dig $affinity "requiredDuringSchedulingIgnoredDuringExecution" list $value }}
+{{- $selectors = append $selectors (dig "labelSelector" dict .) }}
+{{- end }}
+{{- range dig $affinity "preferredDuringSchedulingIgnoredDuringExecution" list $value }}
+{{- $selectors = append $selectors (dig "podAffinityTerm" "labelSelector" dict .) }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- range $selectors }}
+{{- $own := not (empty .matchLabels) }}
+{{- range $k, $v := .matchLabels }}
+{{- if ne (toString $v) (toString (get $podLabels $k)) }}
+{{- $own = false }}
+{{- end }}
+{{- end }}
+{{- if $own }}
+{{- $_ := set . "matchLabels" (merge (dict) .matchLabels $selectorLabels) }}
+{{- end }}
+{{- end }}
+{{- toYaml $value }}
+{{- end }}
+
 {{/*
 Create the name of the service account to use
 */}}
@@ -136,18 +181,76 @@ var chartName = regexp.MustCompile("^[a-zA-Z0-9._-]+$")
 const maxChartNameLength = 250
 
 // initChartDir - creates Helm chart structure in chartName directory if not presented.
-func initChartDir(chartDir, chartName string, crd bool, certManagerAsSubchart bool, certManagerVersion string) error {
-	if err := validateChartName(chartName); err != nil {
+// Chart.yaml metadata from config is applied to a new Chart.yaml and to an existing one.
+func (o output) initChartDir(conf config.Config, values helmify.Values) error {
+	if err := validateChartName(conf.ChartName); err != nil {
 		return err
 	}
 
-	cDir := filepath.Join(chartDir, chartName)
-	_, err := os.Stat(filepath.Join(cDir, "Chart.yaml"))
+	cDir := filepath.Join(conf.ChartDir, conf.ChartName)
+	chartFile := filepath.Join(cDir, "Chart.yaml")
+	content, err := o.fs.ReadFile(chartFile)
 	if os.IsNotExist(err) {
-		return createCommonFiles(chartDir, chartName, crd, certManagerAsSubchart, certManagerVersion)
+		meta := conf.Chart
+		if meta.AppVersion == "" {
+			meta.AppVersion = appVersionFromValues(values)
+		}
+		return o.createCommonFiles(conf.ChartDir, conf.ChartName, conf.Crd, conf.CertManagerAsSubchart, conf.CertManagerVersion, meta)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: unable to read Chart.yaml", err)
 	}
 	logrus.Info("Skip creating Chart skeleton: Chart.yaml already exists.")
-	return err
+	err = o.addMissingHelpers(cDir, conf.ChartName)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(conf.Chart, config.ChartMeta{}) {
+		return nil
+	}
+	content, err = updateChartFile(content, conf.Chart)
+	if err != nil {
+		return err
+	}
+	err = o.fs.WriteFile(chartFile, content, 0640)
+	if err != nil {
+		return fmt.Errorf("%w: unable to write Chart.yaml", err)
+	}
+	logrus.WithField("file", chartFile).Info("updated")
+	return nil
+}
+
+// addedHelpers - helpers introduced after charts may have been created. Generated templates use them,
+// so they are appended to _helpers.tpl of an existing chart if missing there.
+var addedHelpers = []string{"scopeSelectors"}
+
+// addMissingHelpers appends addedHelpers missing in _helpers.tpl of the existing chart. Charts without
+// _helpers.tpl are kept as is.
+func (o output) addMissingHelpers(chartDir, chartName string) error {
+	file := filepath.Join(chartDir, "templates", "_helpers.tpl")
+	content, err := o.fs.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: unable to read _helpers.tpl", err)
+	}
+	var missing [][]byte
+	for _, name := range addedHelpers {
+		if !bytes.Contains(content, []byte(fmt.Sprintf(`define "%s.%s"`, chartName, name))) {
+			missing = append(missing, helperYAML(chartName, name))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	updated := bytes.Join(append([][]byte{bytes.TrimRight(content, "\n")}, missing...), []byte("\n\n"))
+	err = o.fs.WriteFile(file, updated, 0640)
+	if err != nil {
+		return fmt.Errorf("%w: unable to write _helpers.tpl", err)
+	}
+	logrus.WithField("file", file).Info("added missing helpers")
+	return nil
 }
 
 func validateChartName(name string) error {
@@ -160,14 +263,14 @@ func validateChartName(name string) error {
 	return nil
 }
 
-func createCommonFiles(chartDir, chartName string, crd bool, certManagerAsSubchart bool, certManagerVersion string) error {
+func (o output) createCommonFiles(chartDir, chartName string, crd bool, certManagerAsSubchart bool, certManagerVersion string, meta config.ChartMeta) error {
 	cDir := filepath.Join(chartDir, chartName)
-	err := os.MkdirAll(filepath.Join(cDir, "templates"), 0750)
+	err := o.fs.MkdirAll(filepath.Join(cDir, "templates"))
 	if err != nil {
 		return fmt.Errorf("%w: unable create chart/templates dir", err)
 	}
 	if crd {
-		err = os.MkdirAll(filepath.Join(cDir, "crds"), 0750)
+		err = o.fs.MkdirAll(filepath.Join(cDir, "crds"))
 		if err != nil {
 			return fmt.Errorf("%w: unable create crds dir", err)
 		}
@@ -177,12 +280,16 @@ func createCommonFiles(chartDir, chartName string, crd bool, certManagerAsSubcha
 			return
 		}
 		file := filepath.Join(path...)
-		err = os.WriteFile(file, content, 0640)
+		err = o.fs.WriteFile(file, content, 0640)
 		if err == nil {
 			logrus.WithField("file", file).Info("created")
 		}
 	}
-	createFile(chartYAML(chartName, certManagerAsSubchart, certManagerVersion), cDir, "Chart.yaml")
+	chartFile, err := updateChartFile(chartYAML(chartName, certManagerAsSubchart, certManagerVersion), meta)
+	if err != nil {
+		return err
+	}
+	createFile(chartFile, cDir, "Chart.yaml")
 	createFile([]byte(helmIgnore), cDir, ".helmignore")
 	createFile(helpersYAML(chartName), cDir, "templates", "_helpers.tpl")
 	return err
@@ -199,3 +306,14 @@ func chartYAML(appName string, certManagerAsSubchart bool, certManagerVersion st
 func helpersYAML(chartName string) []byte {
 	return []byte(strings.ReplaceAll(defaultHelpers, "<CHARTNAME>", chartName))
 }
+
+// helperYAML returns the default helper with the given name including its comment.
+func helperYAML(chartName, name string) []byte {
+	define := fmt.Sprintf(`{{- define "%s.%s" -}}`, chartName, name)
+	for _, block := range strings.Split(string(helpersYAML(chartName)), "\n\n") {
+		if strings.Contains(block, define) {
+			return []byte(block + "\n")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_scopeSelectorsHelper(t *testing.T) {
+	values := map[string]interface{}{}
+	require.NoError(t, yaml.Unmarshal([]byte(`nameOverride: web-app
+affinity:
+  podAntiAffinity:
+    preferredDuringSchedulingIgnoredDuringExecution:
+    - weight: 100
+      podAffinityTerm:
+        topologyKey: kubernetes.io/hostname
+        labelSelector:
+          matchLabels:
+            app: web
+    requiredDuringSchedulingIgnoredDuringExecution:
+    - topologyKey: zone
+      labelSelector:
+        matchLabels:
+          app: db
+constraints:
+- topologyKey: zone
+  labelSelector:
+    matchLabels:
+      app: web
+- topologyKey: host
+`), &values))
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "chart", Version: "0.1.0", APIVersion: chart.APIVersionV2},
+		Templates: []*chart.File{
+			{Name: "templates/_helpers.tpl", Data: helpersYAML("chart")},
+			{Name: "templates/affinity.yaml", Data: []byte(`{{- include "chart.scopeSelectors" (dict "value" .Values.affinity "podLabels" (dict "app" "web" "tier" "front") "context" $) }}`)},
+			{Name: "templates/constraints.yaml", Data: []byte(`{{- include "chart.scopeSelectors" (dict "value" .Values.constraints "podLabels" (dict "app" "web") "context" $) }}`)},
+		},
+		Values: values,
+	}
+	renderValues, err := chartutil.ToRenderValues(ch, nil, chartutil.ReleaseOptions{Name: "rel"}, nil)
+	require.NoError(t, err)
+	out, err := engine.Render(ch, renderValues)
+	require.NoError(t, err)
+
+	assert.Equal(t, `podAntiAffinity:
+  preferredDuringSchedulingIgnoredDuringExecution:
+  - podAffinityTerm:
+      labelSelector:
+        matchLabels:
+          app: web
+          app.kubernetes.io/instance: rel
+          app.kubernetes.io/name: web-app
+      topologyKey: kubernetes.io/hostname
+    weight: 100
+  requiredDuringSchedulingIgnoredDuringExecution:
+  - labelSelector:
+      matchLabels:
+        app: db
+    topologyKey: zone`, out["chart/templates/affinity.yaml"])
+	assert.Equal(t, `- labelSelector:
+    matchLabels:
+      app: web
+      app.kubernetes.io/instance: rel
+      app.kubernetes.io/name: web-app
+  topologyKey: zone
+- topologyKey: host`, out["chart/templates/constraints.yaml"])
+	selector := values["constraints"].([]interface{})[0].(map[string]interface{})["labelSelector"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"app": "web"}, selector["matchLabels"], "values must not be changed")
+}
+
+func Test_output_addMissingHelpers(t *testing.T) {
+	const helpersFile = "dir/chart/templates/_helpers.tpl"
+	helpers := strings.Replace(string(helpersYAML("chart")), string(helperYAML("chart", "scopeSelectors"))+"\n", "", 1)
+	require.NotContains(t, helpers, "scopeSelectors")
+	mem := newMemFS(nil)
+	require.NoError(t, mem.WriteFile("dir/chart/Chart.yaml", chartYAML("chart", false, ""), 0640))
+	require.NoError(t, mem.WriteFile(helpersFile, []byte(helpers), 0640))
+
+	tpl := testTemplate{
+		data:   `affinity: {{- include "chart.scopeSelectors" (dict "value" .Values.affinity "podLabels" (dict "app" "web") "context" $) | nindent 2 }}`,
+		values: helmify.Values{"affinity": map[string]interface{}{}},
+	}
+	create := func() {
+		err := output{fs: mem}.Create(config.Config{ChartDir: "dir", ChartName: "chart"}, []helmify.Template{tpl}, []string{"deployment.yaml"})
+		require.NoError(t, err)
+	}
+	create()
+	var files []*loader.BufferedFile
+	for name, content := range mem.files {
+		files = append(files, &loader.BufferedFile{Name: strings.TrimPrefix(name, "dir/chart/"), Data: content})
+	}
+	ch, err := loader.LoadFiles(files)
+	require.NoError(t, err)
+	renderValues, err := chartutil.ToRenderValues(ch, nil, chartutil.ReleaseOptions{Name: "rel"}, nil)
+	require.NoError(t, err)
+	out, err := engine.Render(ch, renderValues)
+	require.NoError(t, err, "regenerated templates must render with helpers of the existing chart")
+	assert.Equal(t, "affinity:\n  {}\n", out["chart/templates/deployment.yaml"])
+	assert.True(t, strings.HasPrefix(string(mem.files[helpersFile]), helpers[:len(helpers)-1]), "existing helpers are kept")
+
+	updated := mem.files[helpersFile]
+	create()
+	assert.Equal(t, string(updated), string(mem.files[helpersFile]), "helpers are added once")
+}
@@ -0,0 +1,124 @@
+package helm
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+)
+
+// Chart - in-memory representation of Helm chart.
+type Chart struct {
+	// Name - chart name.
+	Name string
+	// Files - chart files content by slash separated path relative to the chart directory.
+	// Example: "Chart.yaml", "templates/deployment.yaml".
+	Files map[string][]byte
+	// Values - content of values.yaml.
+	Values helmify.Values
+}
+
+// WriteTarGz writes chart files as gzipped tar stream. Files are placed into chart name directory
+// and filtered by .helmignore patterns as in archives created by 'helm package'.
+// Values generated on the previous run are never packaged.
+func (c Chart) WriteTarGz(writer io.Writer) error {
+	rules := parseIgnore(string(c.Files[".helmignore"]))
+	names := make([]string, 0, len(c.Files))
+	for name := range c.Files {
+		if name == generatedValuesFile || rules.Ignore(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gz := gzip.NewWriter(writer)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		content := c.Files[name]
+		err := tw.WriteHeader(&tar.Header{
+			Name:     path.Join(c.Name, name),
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: unable to write tar header for %s", err, name)
+		}
+		_, err = tw.Write(content)
+		if err != nil {
+			return fmt.Errorf("%w: unable to write %s into tar", err, name)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("%w: unable to close tar writer", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("%w: unable to close gzip writer", err)
+	}
+	return nil
+}
+
+// MemoryOutput - helmify.Output keeping the chart in memory. Filesystem is not used.
+type MemoryOutput struct {
+	chart Chart
+}
+
+// NewMemoryOutput creates interface to keep processed input in memory in Helm chart format.
+func NewMemoryOutput() *MemoryOutput {
+	return &MemoryOutput{}
+}
+
+// Create a helm chart in memory. Use Chart to get the result.
+func (o *MemoryOutput) Create(conf config.Config, templates []helmify.Template, filenames []string) error {
+	return o.create(conf, templates, filenames, newMemFS(nil))
+}
+
+// create a helm chart on the given in-memory FS. Chart contains only files written by the output.
+func (o *MemoryOutput) create(conf config.Config, templates []helmify.Template, filenames []string, mem *memFS) error {
+	values, err := output{fs: mem}.create(conf, templates, filenames)
+	if err != nil {
+		return err
+	}
+	chartDir := filepath.Join(conf.ChartDir, conf.ChartName)
+	chart := Chart{Name: conf.ChartName, Files: map[string][]byte{}, Values: values}
+	for name, content := range mem.files {
+		rel, err := filepath.Rel(chartDir, name)
+		if err != nil {
+			return fmt.Errorf("%w: unable to get chart path of %s", err, name)
+		}
+		chart.Files[filepath.ToSlash(rel)] = content
+	}
+	o.chart = chart
+	return nil
+}
+
+// Chart returns the chart created by the last Create call.
+func (o *MemoryOutput) Chart() Chart {
+	return o.chart
+}
+
+// NewTarGzOutput creates interface to write processed input into writer as gzipped tar stream of Helm chart files.
+func NewTarGzOutput(writer io.Writer) helmify.Output {
+	return &tarGzOutput{writer: writer}
+}
+
+type tarGzOutput struct {
+	writer io.Writer
+}
+
+// Create a helm chart in memory and write it as gzipped tar stream.
+func (o tarGzOutput) Create(conf config.Config, templates []helmify.Template, filenames []string) error {
+	mem := NewMemoryOutput()
+	err := mem.Create(conf, templates, filenames)
+	if err != nil {
+		return err
+	}
+	return mem.Chart().WriteTarGz(o.writer)
+}
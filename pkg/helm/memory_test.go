@@ -0,0 +1,77 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+func TestMemoryOutput_Create(t *testing.T) {
+	tpl := testTemplate{data: "replicas: {{ .Values.replicas }}", values: helmify.Values{"replicas": int64(1)}}
+	out := NewMemoryOutput()
+	err := out.Create(config.Config{ChartDir: "not-exists", ChartName: "chart"}, []helmify.Template{tpl}, []string{"deployment.yaml"})
+	require.NoError(t, err)
+	_, err = os.Stat("not-exists")
+	assert.True(t, os.IsNotExist(err))
+
+	chart := out.Chart()
+	assert.Equal(t, "chart", chart.Name)
+	assert.Equal(t, helmify.Values{"replicas": int64(1)}, chart.Values)
+	assert.Equal(t, "replicas: {{ .Values.replicas }}\n", string(chart.Files["templates/deployment.yaml"]))
+	assert.Contains(t, chart.Files, "Chart.yaml")
+	assert.Contains(t, chart.Files, "values.yaml")
+	assert.Contains(t, chart.Files, "templates/_helpers.tpl")
+
+	t.Run("tar gz loaded by helm", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		require.NoError(t, chart.WriteTarGz(&buf))
+		loaded, err := loader.LoadArchive(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, "chart", loaded.Name())
+		assert.Equal(t, float64(1), loaded.Values["replicas"])
+		assert.Len(t, loaded.Templates, 2)
+	})
+	t.Run("tar gz filtered by helmignore", func(t *testing.T) {
+		files := map[string][]byte{generatedValuesFile: []byte("replicas: 1\n"), "notes.bak": nil, ".vscode/settings.json": nil}
+		for name, content := range chart.Files {
+			files[name] = content
+		}
+		names := tarNames(t, Chart{Name: chart.Name, Files: files})
+		assert.Contains(t, names, "chart/values.yaml")
+		assert.Contains(t, names, "chart/.helmignore")
+		assert.NotContains(t, names, "chart/"+generatedValuesFile)
+		assert.NotContains(t, names, "chart/notes.bak")
+		assert.NotContains(t, names, "chart/.vscode/settings.json")
+
+		delete(files, ".helmignore")
+		names = tarNames(t, Chart{Name: chart.Name, Files: files})
+		assert.NotContains(t, names, "chart/"+generatedValuesFile, "generated values are never packaged")
+		assert.Contains(t, names, "chart/notes.bak")
+	})
+}
+
+func tarNames(t *testing.T, chart Chart) []string {
+	buf := bytes.Buffer{}
+	require.NoError(t, chart.WriteTarGz(&buf))
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		require.NoError(t, err)
+		names = append(names, h.Name)
+	}
+}
@@ -0,0 +1,148 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// generatedValuesFile - values generated on the previous run. Used as a base for three-way merge of values.yaml.
+const generatedValuesFile = ".helmify.values.yaml"
+
+// mergeValues performs three-way merge of values.yaml:
+//   - base - values generated on the previous run,
+//   - current - existing values.yaml possibly edited by user,
+//   - generated - values generated on the current run.
+//
+// Values changed, added or deleted by user are kept. Generated values not changed by user are updated, new
+// generated values are added and values not generated anymore are removed. Without base the merge is two-way:
+// every value presented in values.yaml is considered to be set by user and nothing is reported as stale.
+// Returns merged values and paths of the values which are not generated anymore.
+func mergeValues(base, current, generated map[string]interface{}) (map[string]interface{}, []string) {
+	var stale []string
+	res := mergeMaps(base, current, generated, "", base != nil, &stale)
+	sort.Strings(stale)
+	return res, stale
+}
+
+func mergeMaps(base, current, generated map[string]interface{}, path string, hasBase bool, stale *[]string) map[string]interface{} {
+	res := map[string]interface{}{}
+	for key, genVal := range generated {
+		keyPath := strings.TrimPrefix(path+"."+key, ".")
+		baseVal, inBase := base[key]
+		curVal, inCurrent := current[key]
+		switch {
+		case !inCurrent && inBase:
+			// deleted by user
+			logrus.WithField("value", keyPath).Debug("keep value deleted from values.yaml")
+		case !inCurrent:
+			res[key] = genVal
+		default:
+			genMap, genIsMap := genVal.(map[string]interface{})
+			curMap, curIsMap := curVal.(map[string]interface{})
+			if genIsMap && curIsMap {
+				baseMap, _ := baseVal.(map[string]interface{})
+				res[key] = mergeMaps(baseMap, curMap, genMap, keyPath, hasBase, stale)
+				continue
+			}
+			if inBase && reflect.DeepEqual(curVal, baseVal) {
+				res[key] = genVal
+				continue
+			}
+			res[key] = curVal
+		}
+	}
+	for key, curVal := range current {
+		if _, inGenerated := generated[key]; inGenerated {
+			continue
+		}
+		keyPath := strings.TrimPrefix(path+"."+key, ".")
+		baseVal, inBase := base[key]
+		switch {
+		case !hasBase, !inBase:
+			// added by user or no base to tell
+			res[key] = curVal
+		case reflect.DeepEqual(curVal, baseVal):
+			*stale = append(*stale, keyPath)
+		default:
+			// changed by user
+			*stale = append(*stale, keyPath)
+			res[key] = curVal
+		}
+	}
+	return res
+}
+
+// mergeValuesFile merges generated values with values.yaml and previously generated values from chart dir.
+// Stores generated values as a base for the next run.
+func (o output) mergeValuesFile(chartDir string, values helmify.Values) (helmify.Values, error) {
+	generated, err := normalizeValues(values)
+	if err != nil {
+		return nil, err
+	}
+	baseBytes, err := yaml.Marshal(generated)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to marshal generated values", err)
+	}
+	current, err := o.readValues(filepath.Join(chartDir, "values.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		base, err := o.readValues(filepath.Join(chartDir, generatedValuesFile))
+		if err != nil {
+			return nil, err
+		}
+		if base == nil {
+			logrus.Warn("previously generated values not found: values presented in values.yaml are kept as is")
+		}
+		var stale []string
+		generated, stale = mergeValues(base, current, generated)
+		for _, s := range stale {
+			logrus.WithField("value", s).Warn("value is not generated anymore")
+		}
+	}
+	file := filepath.Join(chartDir, generatedValuesFile)
+	err = o.fs.WriteFile(file, baseBytes, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to write %s", err, generatedValuesFile)
+	}
+	return generated, nil
+}
+
+func (o output) readValues(file string) (map[string]interface{}, error) {
+	content, err := o.fs.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to read %s", err, file)
+	}
+	res := map[string]interface{}{}
+	err = yaml.Unmarshal(content, &res)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse %s", err, file)
+	}
+	return res, nil
+}
+
+// normalizeValues converts values to the same types as values read from file to make them comparable.
+func normalizeValues(values helmify.Values) (map[string]interface{}, error) {
+	content, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to marshal values", err)
+	}
+	res := map[string]interface{}{}
+	err = yaml.Unmarshal(content, &res)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to unmarshal values", err)
+	}
+	return res, nil
+}
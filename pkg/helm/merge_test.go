@@ -0,0 +1,77 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mergeValues(t *testing.T) {
+	t.Run("three-way", func(t *testing.T) {
+		base := map[string]interface{}{
+			"app": map[string]interface{}{
+				"replicas":  float64(1),
+				"image":     "nginx:1.0",
+				"removed":   "x",
+				"modified":  "y",
+				"deleted":   "z",
+				"unchanged": "u",
+			},
+		}
+		current := map[string]interface{}{
+			"app": map[string]interface{}{
+				"replicas":  float64(3),
+				"image":     "nginx:1.0",
+				"removed":   "x",
+				"modified":  "user",
+				"unchanged": "u",
+				"userKey":   "v",
+			},
+			"userObj": map[string]interface{}{"a": "b"},
+		}
+		generated := map[string]interface{}{
+			"app": map[string]interface{}{
+				"replicas":  float64(2),
+				"image":     "nginx:2.0",
+				"deleted":   "z",
+				"unchanged": "u",
+				"new":       "n",
+			},
+			"newObj": map[string]interface{}{"a": "b"},
+		}
+		res, stale := mergeValues(base, current, generated)
+		assert.Equal(t, map[string]interface{}{
+			"app": map[string]interface{}{
+				"replicas":  float64(3),
+				"image":     "nginx:2.0",
+				"modified":  "user",
+				"unchanged": "u",
+				"new":       "n",
+				"userKey":   "v",
+			},
+			"userObj": map[string]interface{}{"a": "b"},
+			"newObj":  map[string]interface{}{"a": "b"},
+		}, res)
+		assert.Equal(t, []string{"app.modified", "app.removed"}, stale)
+	})
+	t.Run("two-way without base", func(t *testing.T) {
+		current := map[string]interface{}{
+			"replicas": float64(3),
+			"old":      "x",
+			"app":      map[string]interface{}{"custom": "c"},
+		}
+		generated := map[string]interface{}{
+			"replicas": float64(2),
+			"new":      "n",
+			"app":      map[string]interface{}{"image": "nginx"},
+		}
+		res, stale := mergeValues(nil, current, generated)
+		assert.Equal(t, map[string]interface{}{
+			"replicas": float64(3),
+			"old":      "x",
+			"new":      "n",
+			"app":      map[string]interface{}{"custom": "c", "image": "nginx"},
+		}, res)
+		assert.Empty(t, stale, "values added by user must not be reported without base")
+	})
+}
@@ -0,0 +1,83 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// NewPackageOutput creates interface to dump processed input to filesystem as packaged Helm chart.
+func NewPackageOutput() helmify.Output {
+	return &packageOutput{}
+}
+
+type packageOutput struct{}
+
+// Create a helm chart archive <chartName>-<version>.tgz in the chart dir. Chart directory is not created or changed.
+// An existing chart directory is taken into account as by a normal run: its Chart.yaml, values for conf.MergeValues
+// and files not generated by helmify, e.g. user-added templates, are packaged.
+func (o packageOutput) Create(conf config.Config, templates []helmify.Template, filenames []string) error {
+	mem := NewMemoryOutput()
+	err := mem.create(conf, templates, filenames, newMemFS(osFS{}))
+	if err != nil {
+		return err
+	}
+	chart := mem.Chart()
+	err = addExistingFiles(chart, filepath.Join(conf.ChartDir, conf.ChartName))
+	if err != nil {
+		return err
+	}
+	chartFile := struct {
+		Version string `json:"version"`
+	}{}
+	err = yaml.Unmarshal(chart.Files["Chart.yaml"], &chartFile)
+	if err != nil {
+		return fmt.Errorf("%w: unable to parse Chart.yaml", err)
+	}
+	buf := bytes.Buffer{}
+	err = chart.WriteTarGz(&buf)
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(conf.ChartDir, fmt.Sprintf("%s-%s.tgz", chart.Name, chartFile.Version))
+	err = os.WriteFile(file, buf.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("%w: unable to write %s", err, file)
+	}
+	logrus.WithField("file", file).Info("created")
+	return nil
+}
+
+// addExistingFiles adds files of the chart directory not written by the output to the chart.
+func addExistingFiles(chart Chart, chartDir string) error {
+	err := filepath.WalkDir(chartDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(chartDir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if _, ok := chart.Files[name]; ok {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		chart.Files[name] = content
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("%w: unable to read chart dir %s", err, chartDir)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+func Test_packageOutput_Create(t *testing.T) {
+	dir := t.TempDir()
+	tpl := testTemplate{data: "replicas: {{ .Values.replicas }}", values: helmify.Values{"replicas": int64(1)}}
+	err := NewPackageOutput().Create(config.Config{ChartDir: dir, ChartName: "chart"}, []helmify.Template{tpl}, []string{"deployment.yaml"})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "only archive is expected")
+	assert.Equal(t, "chart-0.1.0.tgz", entries[0].Name())
+
+	loaded, err := loader.Load(filepath.Join(dir, "chart-0.1.0.tgz"))
+	require.NoError(t, err)
+	assert.Equal(t, "0.1.0", loaded.Metadata.Version)
+	assert.Equal(t, "templates/deployment.yaml", loaded.Templates[1].Name)
+}
+
+func Test_packageOutput_Create_existingChart(t *testing.T) {
+	dir := t.TempDir()
+	conf := config.Config{ChartDir: dir, ChartName: "chart"}
+	tpl := testTemplate{data: "replicas: {{ .Values.replicas }}", values: helmify.Values{"replicas": int64(1)}}
+	require.NoError(t, NewOutput().Create(conf, []helmify.Template{tpl}, []string{"deployment.yaml"}))
+	chartFile := filepath.Join(dir, "chart", "Chart.yaml")
+	content, err := os.ReadFile(chartFile)
+	require.NoError(t, err)
+	content = []byte(strings.Replace(string(content), "version: 0.1.0", "version: 1.2.3", 1))
+	require.NoError(t, os.WriteFile(chartFile, content, 0600))
+	userTemplate := filepath.Join(dir, "chart", "templates", "extra.yaml")
+	require.NoError(t, os.WriteFile(userTemplate, []byte("kind: ConfigMap"), 0600))
+
+	tpl.values = helmify.Values{"replicas": int64(2)}
+	require.NoError(t, NewPackageOutput().Create(conf, []helmify.Template{tpl}, []string{"deployment.yaml"}))
+
+	loaded, err := loader.Load(filepath.Join(dir, "chart-1.2.3.tgz"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", loaded.Metadata.Version, "existing Chart.yaml is kept")
+	assert.Equal(t, float64(2), loaded.Values["replicas"])
+	var names []string
+	for _, f := range loaded.Templates {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{"templates/_helpers.tpl", "templates/deployment.yaml", "templates/extra.yaml"}, names)
+
+	existing, err := os.ReadFile(filepath.Join(dir, "chart", "values.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "replicas: 1\n", string(existing), "chart dir is not changed")
+}
@@ -0,0 +1,138 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/sirupsen/logrus"
+)
+
+const schemaDraft = "https://json-schema.org/draft-07/schema#"
+
+var valuesRef = regexp.MustCompile(`\.Values((?:\.[a-zA-Z_][a-zA-Z0-9_]*)+)`)
+
+// valuesSchema infers JSON schema from values and merges it with keywords provided by templates.
+// Objects are closed for unknown properties to catch typos in overrides, except objects rendered by templates
+// as a whole (e.g. toYaml .Values.annotations), because every property of such object ends up in the manifest.
+// Values referenced by templates but missing in values.yaml (e.g. nameOverride) are allowed with any type.
+func valuesSchema(values helmify.Values, keywords helmify.Schema, templates string) ([]byte, error) {
+	refs := map[string]struct{}{}
+	for _, match := range valuesRef.FindAllStringSubmatch(templates, -1) {
+		refs[strings.TrimPrefix(match[1], ".")] = struct{}{}
+	}
+	root := inferSchema(map[string]interface{}(values), "", refs, false)
+	root["$schema"] = schemaDraft
+	for ref := range refs {
+		schemaPath(root, ref)
+	}
+	global := schemaPath(root, "global")
+	global["type"] = "object"
+	for key, kw := range keywords {
+		node := schemaPath(root, key)
+		for keyword, value := range kw {
+			node[keyword] = value
+		}
+	}
+	res, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to marshal values schema", err)
+	}
+	return append(res, '\n'), nil
+}
+
+func inferSchema(value interface{}, path string, refs map[string]struct{}, open bool) map[string]interface{} {
+	switch v := value.(type) {
+	case helmify.Values:
+		return inferSchema(map[string]interface{}(v), path, refs, open)
+	case map[string]interface{}:
+		res := map[string]interface{}{"type": "object"}
+		if _, referenced := refs[path]; referenced {
+			open = true
+		}
+		if len(v) == 0 {
+			return res
+		}
+		properties := map[string]interface{}{}
+		for key, val := range v {
+			properties[key] = inferSchema(val, strings.TrimPrefix(path+"."+key, "."), refs, open)
+		}
+		res["properties"] = properties
+		if !open {
+			res["additionalProperties"] = false
+		}
+		return res
+	case []interface{}:
+		res := map[string]interface{}{"type": "array"}
+		var itemType interface{}
+		for i, item := range v {
+			t := inferSchema(item, "", nil, true)["type"]
+			if t == "object" || t == "array" || (i != 0 && t != itemType) {
+				return res
+			}
+			itemType = t
+		}
+		if itemType != nil {
+			res["items"] = map[string]interface{}{"type": itemType}
+		}
+		return res
+	case []string:
+		return map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+	case string:
+		return map[string]interface{}{"type": "string"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	case int, int32, int64:
+		return map[string]interface{}{"type": "integer"}
+	case float64:
+		// numbers read from yaml are float64
+		if v == math.Trunc(v) {
+			return map[string]interface{}{"type": "integer"}
+		}
+		return map[string]interface{}{"type": "number"}
+	case float32:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// schemaPath returns schema of the value with given dotted path. Creates missing properties without type constraints.
+func schemaPath(root map[string]interface{}, path string) map[string]interface{} {
+	node := root
+	if path == "" {
+		return node
+	}
+	for _, key := range strings.Split(path, ".") {
+		properties, ok := node["properties"].(map[string]interface{})
+		if !ok {
+			properties = map[string]interface{}{}
+			node["properties"] = properties
+		}
+		child, ok := properties[key].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			properties[key] = child
+		}
+		node = child
+	}
+	return node
+}
+
+func (o output) overwriteValuesSchemaFile(chartDir string, values helmify.Values, keywords helmify.Schema, templates string) error {
+	res, err := valuesSchema(values, keywords, templates)
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(chartDir, "values.schema.json")
+	err = o.fs.WriteFile(file, res, 0600)
+	if err != nil {
+		return fmt.Errorf("%w: unable to write values.schema.json", err)
+	}
+	logrus.WithField("file", file).Info("overwritten")
+	return nil
+}
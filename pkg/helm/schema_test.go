@@ -0,0 +1,90 @@
+package helm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_valuesSchema(t *testing.T) {
+	values := helmify.Values{
+		"app": map[string]interface{}{
+			"replicas": int64(1),
+			"image": map[string]interface{}{
+				"repository": "nginx",
+				"tag":        "1.14.2",
+			},
+			"annotations": map[string]interface{}{
+				"a": "b",
+			},
+			"args":   []interface{}{"--v", "--x"},
+			"ports":  []interface{}{map[string]interface{}{"port": int64(80)}},
+			"limits": map[string]interface{}{},
+		},
+		"secret": map[string]interface{}{
+			"token": "",
+		},
+	}
+	keywords := helmify.Schema{}
+	keywords.Add("minimum", 0, "app", "replicas")
+	keywords.Require("secret", "token")
+	templates := `{{ .Values.nameOverride }}
+replicas: {{ .Values.app.replicas }}
+image: {{ .Values.app.image.repository }}:{{ .Values.app.image.tag }}
+annotations:
+  {{- toYaml .Values.app.annotations | nindent 4 }}`
+
+	res, err := valuesSchema(values, keywords, templates)
+	require.NoError(t, err)
+	actual := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(res, &actual))
+
+	expected := map[string]interface{}{
+		"$schema":              schemaDraft,
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"nameOverride": map[string]interface{}{},
+			"global":       map[string]interface{}{"type": "object"},
+			"app": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"replicas": map[string]interface{}{"type": "integer", "minimum": float64(0)},
+					"image": map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": false,
+						"properties": map[string]interface{}{
+							"repository": map[string]interface{}{"type": "string"},
+							"tag":        map[string]interface{}{"type": "string"},
+						},
+					},
+					"annotations": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"a": map[string]interface{}{"type": "string"},
+						},
+					},
+					"args": map[string]interface{}{
+						"type":  "array",
+						"items": map[string]interface{}{"type": "string"},
+					},
+					"ports":  map[string]interface{}{"type": "array"},
+					"limits": map[string]interface{}{"type": "object"},
+				},
+			},
+			"secret": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"required":             []interface{}{"token"},
+				"properties": map[string]interface{}{
+					"token": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, actual)
+}
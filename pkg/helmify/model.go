@@ -26,9 +26,10 @@ type Template interface {
 	Write(writer io.Writer) error
 }
 
-// Output - converts Template into helm chart on disk.
+// Output - converts Templates into helm chart. Chart location is defined by config ChartDir and ChartName.
 type Output interface {
-	Create(chartName, chartDir string, Crd bool, certManagerAsSubchart bool, certManagerVersion string, certManagerInstallCRD bool, templates []Template, filenames []string) error
+	// Create - creates helm chart from templates. filenames[i] is a chart file name for templates[i].
+	Create(conf config.Config, templates []Template, filenames []string) error
 }
 
 // AppMetadata handle common information about K8s objects in the chart.
@@ -47,6 +48,14 @@ type AppMetadata interface {
 	// TrimName trims common prefix from object name if exists.
 	// We trim common prefix because helm already using release for this purpose.
 	TrimName(objName string) string
+	// Autoscaled returns true if object with given kind and name is a scale target of a HorizontalPodAutoscaler.
+	Autoscaled(kind, name string) bool
+	// SelectsChartPods returns true if matchLabels select pods of a workload which pod template is extended
+	// with chart selector labels: Deployment, DaemonSet or Rollout.
+	SelectsChartPods(matchLabels map[string]string) bool
+	// TemplatedNamespace converts namespace to templated Helm namespace according to config namespace mode.
+	// Example:	"my-ns" -> "{{ .Release.Namespace }}" or "{{ .Values.namespaces.myNs }}"
+	TemplatedNamespace(ns string) string
 
 	Config() config.Config
 }
@@ -0,0 +1,59 @@
+package helmify
+
+import "strings"
+
+// Schema - JSON schema keywords for helm values.
+// Keys are value names joined with '.', values are keywords applied to corresponding value schema.
+// Example: Schema{"myApp.replicas": {"minimum": 0}}.
+type Schema map[string]map[string]interface{}
+
+// SchemaProvider - optional Template extension.
+// Implement it to attach additional constraints to the values.schema.json generated from template values.
+type SchemaProvider interface {
+	// Schema - returns JSON schema keywords for template values.
+	Schema() Schema
+}
+
+// Add - adds JSON schema keyword for a value with the given name.
+func (s Schema) Add(keyword string, value interface{}, name ...string) {
+	s.keywords(strings.Join(toCamelCase(name), "."))[keyword] = value
+}
+
+// Require - marks a value with the given name as required in its parent object.
+func (s Schema) Require(name ...string) {
+	name = toCamelCase(name)
+	s.require(strings.Join(name[:len(name)-1], "."), name[len(name)-1])
+}
+
+// Merge given schema with current instance.
+func (s Schema) Merge(schema Schema) {
+	for key, keywords := range schema {
+		for keyword, value := range keywords {
+			if keyword != "required" {
+				s.keywords(key)[keyword] = value
+				continue
+			}
+			for _, r := range value.([]string) {
+				s.require(key, r)
+			}
+		}
+	}
+}
+
+func (s Schema) keywords(key string) map[string]interface{} {
+	if s[key] == nil {
+		s[key] = map[string]interface{}{}
+	}
+	return s[key]
+}
+
+func (s Schema) require(parent, name string) {
+	keywords := s.keywords(parent)
+	required, _ := keywords["required"].([]string)
+	for _, r := range required {
+		if r == name {
+			return
+		}
+	}
+	keywords["required"] = append(required, name)
+}
@@ -0,0 +1,33 @@
+package helmify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	t.Run("keyword added by camel cased name", func(t *testing.T) {
+		s := Schema{}
+		s.Add("minimum", 0, "my-app", "replicas")
+		assert.Equal(t, Schema{"myApp.replicas": {"minimum": 0}}, s)
+	})
+	t.Run("required added to parent once", func(t *testing.T) {
+		s := Schema{}
+		s.Require("secret", "KEY")
+		s.Require("secret", "key")
+		s.Require("root")
+		assert.Equal(t, Schema{
+			"secret": {"required": []string{"key"}},
+			"":       {"required": []string{"root"}},
+		}, s)
+	})
+	t.Run("merge", func(t *testing.T) {
+		s := Schema{"a": {"required": []string{"b"}}, "a.b": {"type": "string"}}
+		s.Merge(Schema{"a": {"required": []string{"b", "c"}}, "a.b": {"minLength": 1}})
+		assert.Equal(t, Schema{
+			"a":   {"required": []string{"b", "c"}},
+			"a.b": {"type": "string", "minLength": 1},
+		}, s)
+	})
+}
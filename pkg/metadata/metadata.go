@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -13,6 +14,12 @@ import (
 
 const nameTeml = `{{ include "%s.fullname" . }}-%s`
 
+const (
+	releaseNamespace = "{{ .Release.Namespace }}"
+	// namespacesKey - values key for app namespaces in config.NamespaceValues mode.
+	namespacesKey = "namespaces"
+)
+
 var nsGVK = schema.GroupVersionKind{
 	Group:   "",
 	Version: "v1",
@@ -25,14 +32,35 @@ var crdGVK = schema.GroupVersionKind{
 	Kind:    "CustomResourceDefinition",
 }
 
+var hpaGVK = schema.GroupVersionKind{
+	Group:   "autoscaling",
+	Version: "v2",
+	Kind:    "HorizontalPodAutoscaler",
+}
+
+// chartPodsGKs - workloads which pod templates are extended with chart selector labels.
+var chartPodsGKs = map[schema.GroupKind]struct{}{
+	{Group: "apps", Kind: "Deployment"}:     {},
+	{Group: "apps", Kind: "DaemonSet"}:      {},
+	{Group: "argoproj.io", Kind: "Rollout"}: {},
+}
+
 func New(conf config.Config) *Service {
-	return &Service{names: make(map[string]struct{}), conf: conf}
+	return &Service{
+		names:        make(map[string]struct{}),
+		scaleTargets: make(map[string]struct{}),
+		namespaces:   make(map[string]struct{}),
+		conf:         conf,
+	}
 }
 
 type Service struct {
 	commonPrefix string
 	namespace    string
+	namespaces   map[string]struct{}
 	names        map[string]struct{}
+	scaleTargets map[string]struct{}
+	podLabels    []map[string]string
 	conf         config.Config
 }
 
@@ -40,6 +68,13 @@ func (a *Service) Config() config.Config {
 	return a.conf
 }
 
+// WithConfig returns a copy of the service with given config. Used to apply per-object options after Load.
+func (a *Service) WithConfig(conf config.Config) *Service {
+	res := *a
+	res.conf = conf
+	return &res
+}
+
 // TrimName - tries to trim app common prefix for object name if detected.
 // If no common prefix - returns name as it is.
 // It is better to trim common prefix because Helm also adds release name as common prefix.
@@ -58,12 +93,23 @@ var _ helmify.AppMetadata = &Service{}
 // other app meta information.
 func (a *Service) Load(obj *unstructured.Unstructured) {
 	a.names[obj.GetName()] = struct{}{}
+	if obj.GroupVersionKind() == hpaGVK {
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "kind")
+		name, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "name")
+		a.scaleTargets[kind+"/"+name] = struct{}{}
+	}
+	if _, ok := chartPodsGKs[obj.GroupVersionKind().GroupKind()]; ok {
+		labels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+		a.podLabels = append(a.podLabels, labels)
+	}
 	a.commonPrefix = detectCommonPrefix(obj, a.commonPrefix)
 	objNs := extractAppNamespace(obj)
 	if objNs == "" {
 		return
 	}
-	if a.namespace != "" && a.namespace != objNs {
+	a.namespaces[objNs] = struct{}{}
+	mode := a.conf.Namespaces()
+	if a.namespace != "" && a.namespace != objNs && (mode == config.NamespaceOmit || mode == config.NamespaceRelease) {
 		logrus.Warnf("Two different namespaces for app detected: %s and %s. Resulted char will have single namespace.", objNs, a.namespace)
 	}
 	a.namespace = objNs
@@ -74,6 +120,66 @@ func (a *Service) Namespace() string {
 	return a.namespace
 }
 
+// TemplatedNamespace converts namespace to its Helm templated representation according to config namespace mode.
+// Only app namespaces are templated, other namespaces are returned as is.
+func (a *Service) TemplatedNamespace(ns string) string {
+	mode := a.conf.Namespaces()
+	if ns == "" {
+		if mode == config.NamespacePreserve {
+			return ""
+		}
+		return releaseNamespace
+	}
+	if _, contains := a.namespaces[ns]; !contains || mode == config.NamespacePreserve {
+		return ns
+	}
+	if mode == config.NamespaceValues {
+		return fmt.Sprintf("{{ .Values.%s.%s }}", namespacesKey, strcase.ToLowerCamel(ns))
+	}
+	return releaseNamespace
+}
+
+// NamespaceValues returns values with app namespaces if namespaces are moved into values.
+func (a *Service) NamespaceValues() helmify.Values {
+	if a.conf.Namespaces() != config.NamespaceValues || len(a.namespaces) == 0 {
+		return nil
+	}
+	namespaces := map[string]interface{}{}
+	for ns := range a.namespaces {
+		namespaces[strcase.ToLowerCamel(ns)] = ns
+	}
+	return helmify.Values{namespacesKey: namespaces}
+}
+
+// Autoscaled returns true if object with given kind and name is a scale target of a HorizontalPodAutoscaler.
+func (a *Service) Autoscaled(kind, name string) bool {
+	_, contains := a.scaleTargets[kind+"/"+name]
+	return contains
+}
+
+// SelectsChartPods returns true if matchLabels select pods of a workload which pod template is extended
+// with chart selector labels.
+func (a *Service) SelectsChartPods(matchLabels map[string]string) bool {
+	if len(matchLabels) == 0 {
+		return false
+	}
+	for _, labels := range a.podLabels {
+		if isSubset(matchLabels, labels) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSubset(subset, labels map[string]string) bool {
+	for k, v := range subset {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
 // ChartName returns ChartName.
 func (a *Service) ChartName() string {
 	return a.conf.ChartName
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -115,6 +116,58 @@ func Test_Service(t *testing.T) {
 		assert.Equal(t, "qwe", testSvc.TemplatedName("qwe"))
 		assert.NotEqual(t, "abc", testSvc.TemplatedName("abc"))
 	})
+	t.Run("template namespace", func(t *testing.T) {
+		for mode, expected := range map[config.NamespaceMode][3]string{
+			config.NamespaceOmit:     {"{{ .Release.Namespace }}", "kube-system", "{{ .Release.Namespace }}"},
+			config.NamespacePreserve: {"my-monitoring", "kube-system", ""},
+			config.NamespaceRelease:  {"{{ .Release.Namespace }}", "kube-system", "{{ .Release.Namespace }}"},
+			config.NamespaceValues:   {"{{ .Values.namespaces.myMonitoring }}", "kube-system", "{{ .Release.Namespace }}"},
+		} {
+			testSvc := New(config.Config{NamespaceMode: mode})
+			testSvc.Load(createRes("abc", "my-monitoring"))
+			assert.Equal(t, expected[0], testSvc.TemplatedNamespace("my-monitoring"), mode)
+			assert.Equal(t, expected[1], testSvc.TemplatedNamespace("kube-system"), mode)
+			assert.Equal(t, expected[2], testSvc.TemplatedNamespace(""), mode)
+		}
+	})
+	t.Run("namespace values", func(t *testing.T) {
+		testSvc := New(config.Config{NamespaceMode: config.NamespaceValues})
+		testSvc.Load(createRes("abc", "my-monitoring"))
+		testSvc.Load(createRes("qwe", "app"))
+		assert.Equal(t, helmify.Values{"namespaces": map[string]interface{}{"myMonitoring": "my-monitoring", "app": "app"}}, testSvc.NamespaceValues())
+
+		testSvc = New(config.Config{NamespaceMode: config.NamespaceRelease})
+		testSvc.Load(createRes("abc", "my-monitoring"))
+		assert.Nil(t, testSvc.NamespaceValues())
+	})
+}
+
+func Test_Service_SelectsChartPods(t *testing.T) {
+	testSvc := New(config.Config{})
+	testSvc.Load(internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+        tier: frontend`))
+	testSvc.Load(internal.GenerateObj(`apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  template:
+    metadata:
+      labels:
+        app: db`))
+	assert.True(t, testSvc.SelectsChartPods(map[string]string{"app": "web"}))
+	assert.True(t, testSvc.SelectsChartPods(map[string]string{"app": "web", "tier": "frontend"}))
+	assert.False(t, testSvc.SelectsChartPods(map[string]string{"app": "web", "tier": "backend"}))
+	assert.False(t, testSvc.SelectsChartPods(map[string]string{"app": "db"}), "statefulset pods have no chart selector labels")
+	assert.False(t, testSvc.SelectsChartPods(nil))
 }
 
 func createRes(name, ns string) *unstructured.Unstructured {
@@ -117,7 +117,7 @@ func (c crd) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 			wh := conv.Webhook
 			if wh != nil && wh.ClientConfig != nil && wh.ClientConfig.Service != nil {
 				wh.ClientConfig.Service.Name = appMeta.TemplatedName(wh.ClientConfig.Service.Name)
-				wh.ClientConfig.Service.Namespace = strings.ReplaceAll(wh.ClientConfig.Service.Namespace, appMeta.Namespace(), `{{ .Release.Namespace }}`)
+				wh.ClientConfig.Service.Namespace = appMeta.TemplatedNamespace(wh.ClientConfig.Service.Namespace)
 			}
 		}
 	}
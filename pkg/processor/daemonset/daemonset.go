@@ -81,24 +81,30 @@ func (d daemonset) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstru
 	selector = strings.Trim(selector, " \n")
 	selector = string(yamlformat.Indent([]byte(selector), 4))
 
-	podLabels, err := yamlformat.Marshal(dae.Spec.Template.ObjectMeta.Labels, 8)
+	nameCamel := strcase.ToLowerCamel(name)
+	podMeta, err := pod.TemplateMeta(nameCamel, appMeta, dae.Spec.Template.ObjectMeta, values)
+	if err != nil {
+		return true, nil, err
+	}
+	podLabels, err := yamlformat.Marshal(podMeta["labels"], 8)
 	if err != nil {
 		return true, nil, err
 	}
+	podLabels = pod.RenderDefaults(podLabels)
 	podLabels += fmt.Sprintf("\n      {{- include \"%s.selectorLabels\" . | nindent 8 }}", appMeta.ChartName())
 
 	podAnnotations := ""
-	if len(dae.Spec.Template.ObjectMeta.Annotations) != 0 {
-		podAnnotations, err = yamlformat.Marshal(map[string]interface{}{"annotations": dae.Spec.Template.ObjectMeta.Annotations}, 6)
+	delete(podMeta, "labels")
+	if len(podMeta) != 0 {
+		podAnnotations, err = yamlformat.Marshal(podMeta, 6)
 		if err != nil {
 			return true, nil, err
 		}
 
-		podAnnotations = "\n" + podAnnotations
+		podAnnotations = "\n" + pod.RenderDefaults(podAnnotations)
 	}
 
-	nameCamel := strcase.ToLowerCamel(name)
-	specMap, podValues, err := pod.ProcessSpec(nameCamel, appMeta, dae.Spec.Template.Spec)
+	specMap, podValues, err := pod.ProcessSpec(nameCamel, appMeta, dae.Spec.Template.Spec, dae.Spec.Template.Labels)
 	if err != nil {
 		return true, nil, err
 	}
@@ -112,9 +118,11 @@ func (d daemonset) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstru
 		return true, nil, err
 	}
 	spec = strings.ReplaceAll(spec, "'", "")
+	spec = pod.RenderDefaults(spec)
 
 	return true, &result{
 		values: values,
+		schema: pod.Schema(nameCamel, dae.Spec.Template.Spec),
 		data: struct {
 			Meta           string
 			Selector       string
@@ -140,6 +148,7 @@ type result struct {
 		Spec           string
 	}
 	values helmify.Values
+	schema helmify.Schema
 }
 
 func (r *result) Filename() string {
@@ -150,6 +159,10 @@ func (r *result) Values() helmify.Values {
 	return r.values
 }
 
+func (r *result) Schema() helmify.Schema {
+	return r.schema
+}
+
 func (r *result) Write(writer io.Writer) error {
 	return daemonsetTempl.Execute(writer, r.data)
 }
@@ -120,23 +120,23 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 			expectedSpec: map[string]interface{}{
 				"containers": []interface{}{
 					map[string]interface{}{
-						"env": []interface{}{
-							map[string]interface{}{
-								"name":  "KUBERNETES_CLUSTER_DOMAIN",
-								"value": "{{ quote .Values.kubernetesClusterDomain }}",
-							},
-						},
-						"image": "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
-						"name":  "test-container",
+						"helmifyDefault.env": "test.testContainer.extraEnv",
+						"image":              "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
+						"name":               "test-container",
 						"volumeMounts": []interface{}{
 							"{{- toYaml .Values.test.testContainer.volumeMounts.hostData | nindent 10 }}",
+							map[string]interface{}{"helmifyDefaultEntries": "test.testContainer.extraVolumeMounts"},
 						},
-						"resources": map[string]interface{}{},
+						"resources":              map[string]interface{}{},
+						"helmifyDefault.envFrom": "test.testContainer.extraEnvFrom",
 					},
+					map[string]interface{}{"helmifyDefaultEntries": "test.extraContainers"},
 				},
 				"volumes": []interface{}{
-					"{{- toYaml .Values.test.volumes.hostData | nindent 8 }}",
+					"{{- tpl (toYaml .Values.test.volumes.hostData) $ | nindent 8 }}",
+					map[string]interface{}{"helmifyDefaultEntries": "test.extraVolumes"},
 				},
+				"helmifyDefault.initContainers": "test.extraInitContainers",
 			},
 			expectedPodValues: map[string]interface{}{
 				"test": map[string]interface{}{
@@ -151,6 +151,9 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 								"name":      "host-data",
 							},
 						},
+						"extraEnv":          []interface{}{},
+						"extraEnvFrom":      []interface{}{},
+						"extraVolumeMounts": []interface{}{},
 					},
 					"volumes": map[string]interface{}{
 						"hostData": map[string]interface{}{
@@ -161,6 +164,9 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 							},
 						},
 					},
+					"extraVolumes":        []interface{}{},
+					"extraContainers":     []interface{}{},
+					"extraInitContainers": []interface{}{},
 				},
 			},
 		},
@@ -200,23 +206,23 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 			expectedSpec: map[string]interface{}{
 				"containers": []interface{}{
 					map[string]interface{}{
-						"env": []interface{}{
-							map[string]interface{}{
-								"name":  "KUBERNETES_CLUSTER_DOMAIN",
-								"value": "{{ quote .Values.kubernetesClusterDomain }}",
-							},
-						},
-						"image": "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
-						"name":  "test-container",
+						"helmifyDefault.env": "test.testContainer.extraEnv",
+						"image":              "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
+						"name":               "test-container",
 						"volumeMounts": []interface{}{
 							"{{- toYaml .Values.test.testContainer.volumeMounts.csiVolume | nindent 10 }}",
+							map[string]interface{}{"helmifyDefaultEntries": "test.testContainer.extraVolumeMounts"},
 						},
-						"resources": map[string]interface{}{},
+						"resources":              map[string]interface{}{},
+						"helmifyDefault.envFrom": "test.testContainer.extraEnvFrom",
 					},
+					map[string]interface{}{"helmifyDefaultEntries": "test.extraContainers"},
 				},
 				"volumes": []interface{}{
-					"{{- toYaml .Values.test.volumes.csiVolume | nindent 8 }}",
+					"{{- tpl (toYaml .Values.test.volumes.csiVolume) $ | nindent 8 }}",
+					map[string]interface{}{"helmifyDefaultEntries": "test.extraVolumes"},
 				},
+				"helmifyDefault.initContainers": "test.extraInitContainers",
 			},
 			expectedPodValues: map[string]interface{}{
 				"test": map[string]interface{}{
@@ -231,6 +237,9 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 								"name":      "csi-volume",
 							},
 						},
+						"extraEnv":          []interface{}{},
+						"extraEnvFrom":      []interface{}{},
+						"extraVolumeMounts": []interface{}{},
 					},
 					"volumes": map[string]interface{}{
 						"csiVolume": map[string]interface{}{
@@ -243,6 +252,9 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 							},
 						},
 					},
+					"extraVolumes":        []interface{}{},
+					"extraContainers":     []interface{}{},
+					"extraInitContainers": []interface{}{},
 				},
 			},
 		},
@@ -255,7 +267,7 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 			}
 			appMeta := metadata.New(*cfg)
 
-			got, gotPodValues, err := pod.ProcessSpec("test", appMeta, tt.spec.Template.Spec)
+			got, gotPodValues, err := pod.ProcessSpec("test", appMeta, tt.spec.Template.Spec, nil)
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedSpec, map[string]interface{}(got))
 			assert.Equal(t, tt.expectedPodValues, map[string]interface{}(gotPodValues))
@@ -1,12 +1,21 @@
 package processor
 
 import (
+	"fmt"
 	"io"
+	"slices"
+	"strings"
 
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/fieldpath"
 	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/processor/pod"
 	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -23,7 +32,12 @@ func Default() helmify.Processor {
 
 type dft struct{}
 
+// defaultPodSpec - path of the pod spec detected in unknown resources, e.g. in Knative Service or OpenKruise CloneSet.
+const defaultPodSpec = "spec.template.spec"
+
 // Process unknown resource to a helm template. Default processor just templates obj name and adds helm annotations.
+// Pod specs embedded into the resource are templated the same way as for Deployment, see config.ObjectOptions.PodSpecs.
+// Top-level fields are moved into values if enabled, see config.Config.LiftedFields.
 func (d dft) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
 	if obj.GroupVersionKind() == nsGVK {
 		// Skip namespaces from processing because namespace will be handled by Helm.
@@ -40,23 +54,163 @@ func (d dft) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 	if err != nil {
 		return true, nil, err
 	}
+	gvk := obj.GroupVersionKind()
+	opts := appMeta.Config().ObjectOptions(config.QualifiedKind(gvk.Group, gvk.Kind), obj.GetName())
+	res := &defaultResult{name: name, values: helmify.Values{}, schema: helmify.Schema{}}
+	podFields, err := processPodSpecs(appMeta, obj, opts.PodSpecs, strcase.ToLowerCamel(name), res)
+	if err != nil {
+		return true, nil, err
+	}
+	lifted, err := liftFields(appMeta, obj, podFields, res)
+	if err != nil {
+		return true, nil, err
+	}
 	delete(obj.Object, "apiVersion")
 	delete(obj.Object, "kind")
 	delete(obj.Object, "metadata")
 
-	body, err := yamlformat.Marshal(obj.Object, 0)
+	templated := len(podFields) != 0 || lifted
+	marshal := yamlformat.Marshal
+	if templated {
+		marshal = yamlformat.MarshalTemplated
+	}
+	body, err := marshal(obj.Object, 0)
 	if err != nil {
 		return true, nil, err
 	}
-	return true, &defaultResult{
-		data: []byte(meta + "\n" + body),
-		name: name,
-	}, nil
+	if templated {
+		body = pod.RenderDefaults(body)
+	}
+	res.data = []byte(meta + "\n" + body)
+	return true, res, nil
+}
+
+// processPodSpecs templates pod specs embedded into the object in place.
+// Returns top-level fields of the object containing templated pod specs.
+func processPodSpecs(appMeta helmify.AppMetadata, obj *unstructured.Unstructured, paths []string, name string, res *defaultResult) ([]string, error) {
+	if paths == nil {
+		if _, ok, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers"); !ok {
+			return nil, nil
+		}
+		paths = []string{defaultPodSpec}
+	}
+	var fields []string
+	for _, p := range paths {
+		path, err := fieldpath.Parse(p)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := path.Get(obj.Object)
+		specMap, isMap := value.(map[string]interface{})
+		if !ok || !isMap {
+			logrus.WithFields(logrus.Fields{"Kind": obj.GetKind(), "Name": obj.GetName(), "Path": p}).Debug("pod spec not found")
+			continue
+		}
+		specName := name
+		if len(paths) > 1 {
+			specName = strcase.ToLowerCamel(name + " " + p)
+		}
+		spec := corev1.PodSpec{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(specMap, &spec)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to cast %s to pod spec", err, p)
+		}
+		if !namedContainers(spec) {
+			logrus.WithFields(logrus.Fields{"Kind": obj.GetKind(), "Name": obj.GetName(), "Path": p}).
+				Warn("pod spec has containers without name: keeping it as is")
+			continue
+		}
+		err = processPodSpec(appMeta, specName, spec, specMap, 2*len(path), res)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to process pod spec %s", err, p)
+		}
+		fields = append(fields, path[0].Key)
+	}
+	return fields, nil
+}
+
+// processPodSpec replaces content of the specMap with the spec template. Fields unknown to corev1.PodSpec are kept.
+// indent is the indentation of the spec fields in the marshalled object.
+func processPodSpec(appMeta helmify.AppMetadata, name string, spec corev1.PodSpec, specMap map[string]interface{}, indent int, res *defaultResult) error {
+	templated, values, err := pod.ProcessSpec(name, appMeta, spec, nil)
+	if err != nil {
+		return err
+	}
+	err = res.values.Merge(values)
+	if err != nil {
+		return err
+	}
+	res.schema.Merge(pod.Schema(name, spec))
+	for k, v := range templated {
+		// pod.ProcessSpec indents values for the Deployment pod spec fields
+		specMap[k] = pod.ShiftIndent(v, indent-pod.SpecIndent)
+	}
+	return nil
+}
+
+// liftFields moves top-level fields of the object into values under <kindCamel>.<nameCamel>.<field>.
+// Fields with templated pod specs are not lifted. Returns false if nothing is lifted.
+func liftFields(appMeta helmify.AppMetadata, obj *unstructured.Unstructured, podFields []string, res *defaultResult) (bool, error) {
+	gvk := obj.GroupVersionKind()
+	fields := appMeta.Config().LiftedFields(config.QualifiedKind(gvk.Group, gvk.Kind), obj.GetName())
+	kindCamel := strcase.ToLowerCamel(gvk.Kind)
+	nameCamel := strcase.ToLowerCamel(appMeta.TrimName(obj.GetName()))
+	lifted := false
+	for _, field := range fields {
+		value, ok := obj.Object[field]
+		if !ok {
+			continue
+		}
+		if slices.Contains(podFields, field) {
+			logrus.WithFields(logrus.Fields{"Kind": obj.GetKind(), "Name": obj.GetName(), "Field": field}).
+				Info("field contains templated pod spec: not lifted into values")
+			continue
+		}
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			err := unstructured.SetNestedField(res.values, value, kindCamel, nameCamel, field)
+			if err != nil {
+				return false, fmt.Errorf("%w: unable to set %s value", err, field)
+			}
+			if hasTemplatedNames(appMeta, value) {
+				// references to chart objects are rewritten to templated names
+				obj.Object[field] = fmt.Sprintf("{{- tpl (toYaml .Values.%s.%s.%s) $ | nindent 2 }}", kindCamel, nameCamel, field)
+			} else {
+				obj.Object[field] = fmt.Sprintf("{{- toYaml .Values.%s.%s.%s | nindent 2 }}", kindCamel, nameCamel, field)
+			}
+		default:
+			tpl, err := res.values.Add(value, kindCamel, nameCamel, field)
+			if err != nil {
+				return false, fmt.Errorf("%w: unable to set %s value", err, field)
+			}
+			obj.Object[field] = tpl
+		}
+		lifted = true
+	}
+	return lifted, nil
+}
+
+// hasTemplatedNames returns true if value contains templated names of chart objects.
+func hasTemplatedNames(appMeta helmify.AppMetadata, value interface{}) bool {
+	return strings.Contains(fmt.Sprint(value), fmt.Sprintf(`{{ include "%s.fullname" . }}`, appMeta.ChartName()))
+}
+
+func namedContainers(spec corev1.PodSpec) bool {
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, c := range containers {
+			if c.Name == "" {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 type defaultResult struct {
-	data []byte
-	name string
+	data   []byte
+	name   string
+	values helmify.Values
+	schema helmify.Schema
 }
 
 func (r *defaultResult) Filename() string {
@@ -64,7 +218,11 @@ func (r *defaultResult) Filename() string {
 }
 
 func (r *defaultResult) Values() helmify.Values {
-	return helmify.Values{}
+	return r.values
+}
+
+func (r *defaultResult) Schema() helmify.Schema {
+	return r.schema
 }
 
 func (r *defaultResult) Write(writer io.Writer) error {
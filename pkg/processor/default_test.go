@@ -1,12 +1,17 @@
 package processor
 
 import (
-	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"bytes"
 	"testing"
 
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+
 	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 	"github.com/EdgeGamingGG/helmify/pkg/metadata"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 const pvcYaml = `apiVersion: v1
@@ -21,6 +26,191 @@ spec:
       storage: 2Gi
   storageClassName: cust1-mypool-lim`
 
+const cloneSetYaml = `apiVersion: apps.kruise.io/v1alpha1
+kind: CloneSet
+metadata:
+  name: my-operator-web
+spec:
+  replicas: 2
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25.1
+        resources:
+          limits:
+            cpu: 100m
+      volumes:
+      - name: data
+        emptyDir: {}`
+
+const scaledJobYaml = `apiVersion: keda.sh/v1alpha1
+kind: ScaledJob
+metadata:
+  name: my-operator-worker
+spec:
+  jobTargetRef:
+    template:
+      spec:
+        containers:
+        - name: worker
+          image: worker:1.0.0
+        restartPolicy: Never
+        customField: kept`
+
+func processDefault(t *testing.T, conf config.Config, objYaml string) (helmify.Values, string) {
+	obj := internal.GenerateObj(objYaml)
+	conf.ChartName = "chart-name"
+	testMeta := metadata.New(conf)
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj("apiVersion: v1\nkind: Service\nmetadata:\n  name: my-operator-svc"))
+	processed, templ, err := Default().Process(testMeta, obj)
+	require.NoError(t, err)
+	require.True(t, processed)
+	buf := bytes.Buffer{}
+	require.NoError(t, templ.Write(&buf))
+	return templ.Values(), buf.String()
+}
+
+func Test_dft_Process_podSpec(t *testing.T) {
+	t.Run("detected", func(t *testing.T) {
+		values, out := processDefault(t, config.Config{}, cloneSetYaml)
+		assert.Contains(t, out, "image: {{ .Values.web.web.image.repository }}")
+		assert.Contains(t, out, "{{- toYaml .Values.web.web.resources | nindent 10 }}")
+		assert.Contains(t, out, "{{- tpl (toYaml .Values.web.volumes.data) $ | nindent 8 }}")
+		repo, _, _ := unstructured.NestedString(values, "web", "web", "image", "repository")
+		assert.Equal(t, "nginx", repo)
+	})
+	t.Run("configured path", func(t *testing.T) {
+		conf := config.Config{Kinds: map[string]config.ObjectOptions{
+			"keda.sh/ScaledJob": {PodSpecs: []string{"spec.jobTargetRef.template.spec"}},
+		}}
+		values, out := processDefault(t, conf, scaledJobYaml)
+		assert.Contains(t, out, "image: {{ .Values.worker.worker.image.repository }}")
+		assert.Contains(t, out, "customField: kept")
+		assert.Contains(t, out, "restartPolicy: Never")
+		tag, _, _ := unstructured.NestedString(values, "worker", "worker", "image", "tag")
+		assert.Equal(t, "1.0.0", tag)
+	})
+	t.Run("indent shifted", func(t *testing.T) {
+		conf := config.Config{Kinds: map[string]config.ObjectOptions{
+			"ScaledJob": {PodSpecs: []string{"spec.jobTargetRef.template.spec"}},
+		}}
+		_, out := processDefault(t, conf, `apiVersion: keda.sh/v1alpha1
+kind: ScaledJob
+metadata:
+  name: my-operator-worker
+spec:
+  jobTargetRef:
+    template:
+      spec:
+        containers:
+        - name: worker
+          image: worker:1.0.0
+          args: [run]`)
+		assert.Contains(t, out, "{{- toYaml .Values.worker.worker.args | nindent 10 }}")
+	})
+	t.Run("quoted values kept", func(t *testing.T) {
+		_, out := processDefault(t, config.Config{}, cloneSetYaml+`
+  updateStrategy:
+    paused: "*"
+    reason: "it's ok"
+    maxUnavailable: "1"`)
+		assert.Contains(t, out, "paused: '*'")
+		assert.Contains(t, out, "reason: it's ok")
+		assert.Contains(t, out, `maxUnavailable: "1"`)
+		assert.Contains(t, out, "image: {{ .Values.web.web.image.repository }}")
+	})
+	t.Run("disabled", func(t *testing.T) {
+		conf := config.Config{Kinds: map[string]config.ObjectOptions{"CloneSet": {PodSpecs: []string{}}}}
+		values, out := processDefault(t, conf, cloneSetYaml)
+		assert.Empty(t, values)
+		assert.Contains(t, out, "image: nginx:1.25.1")
+	})
+	t.Run("unnamed container", func(t *testing.T) {
+		values, out := processDefault(t, config.Config{}, `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: my-operator-web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.25.1`)
+		assert.Empty(t, values)
+		assert.Contains(t, out, "image: nginx:1.25.1")
+	})
+}
+
+func Test_dft_Process_lift(t *testing.T) {
+	const settingsYaml = `apiVersion: example.com/v1
+kind: AppSettings
+metadata:
+  name: my-operator-config
+mode: fast
+data:
+  key: value
+spec:
+  replicas: 2
+  selector:
+    app: web`
+	t.Run("disabled by default", func(t *testing.T) {
+		values, out := processDefault(t, config.Config{}, settingsYaml)
+		assert.Empty(t, values)
+		assert.Contains(t, out, "replicas: 2")
+	})
+	t.Run("spec", func(t *testing.T) {
+		values, out := processDefault(t, config.Config{LiftSpec: true}, settingsYaml)
+		assert.Contains(t, out, "spec: {{- toYaml .Values.appSettings.config.spec | nindent 2 }}")
+		assert.Contains(t, out, "mode: fast")
+		replicas, _, _ := unstructured.NestedInt64(values, "appSettings", "config", "spec", "replicas")
+		assert.EqualValues(t, 2, replicas)
+	})
+	t.Run("selected fields", func(t *testing.T) {
+		conf := config.Config{LiftSpec: true, Kinds: map[string]config.ObjectOptions{
+			"example.com/AppSettings": {Lift: []string{"data", "mode", "missing"}},
+		}}
+		values, out := processDefault(t, conf, settingsYaml)
+		assert.Contains(t, out, "data: {{- toYaml .Values.appSettings.config.data | nindent 2 }}")
+		assert.Contains(t, out, "mode: {{ .Values.appSettings.config.mode | quote }}")
+		assert.Contains(t, out, "replicas: 2")
+		mode, _, _ := unstructured.NestedString(values, "appSettings", "config", "mode")
+		assert.Equal(t, "fast", mode)
+	})
+	t.Run("templated references", func(t *testing.T) {
+		values, out := processDefault(t, config.Config{LiftSpec: true}, `apiVersion: example.com/v1
+kind: AppSettings
+metadata:
+  name: my-operator-config
+spec:
+  service: '{{ include "chart-name.fullname" . }}-svc'`)
+		assert.Contains(t, out, "spec: {{- tpl (toYaml .Values.appSettings.config.spec) $ | nindent 2 }}")
+		service, _, _ := unstructured.NestedString(values, "appSettings", "config", "spec", "service")
+		assert.Equal(t, `{{ include "chart-name.fullname" . }}-svc`, service)
+	})
+	t.Run("quoted values kept", func(t *testing.T) {
+		conf := config.Config{Kinds: map[string]config.ObjectOptions{
+			"example.com/AppSettings": {Lift: []string{"mode"}},
+		}}
+		_, out := processDefault(t, conf, settingsYaml+`
+  hosts: "*"
+  reason: "it's ok"
+  port: "8080"`)
+		assert.Contains(t, out, "mode: {{ .Values.appSettings.config.mode | quote }}")
+		assert.Contains(t, out, "hosts: '*'")
+		assert.Contains(t, out, "reason: it's ok")
+		assert.Contains(t, out, `port: "8080"`)
+	})
+	t.Run("pod spec not lifted", func(t *testing.T) {
+		values, out := processDefault(t, config.Config{LiftSpec: true}, cloneSetYaml)
+		assert.Contains(t, out, "image: {{ .Values.web.web.image.repository }}")
+		assert.NotContains(t, values, "cloneSet")
+	})
+}
+
 func Test_dft_Process(t *testing.T) {
 
 	t.Run("skip namespace", func(t *testing.T) {
@@ -82,6 +82,10 @@ func (d deployment) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstr
 	if err != nil {
 		return true, nil, err
 	}
+	if replicas != "" && appMeta.Autoscaled(obj.GetKind(), obj.GetName()) {
+		// replicas are managed by HorizontalPodAutoscaler when autoscaling is enabled
+		replicas = fmt.Sprintf("  {{- if not .Values.%s.autoscaling.enabled }}\n%s\n  {{- end }}", strcase.ToLowerCamel(name), replicas)
+	}
 
 	revisionHistoryLimit, err := processRevisionHistoryLimit(name, &depl, &values)
 	if err != nil {
@@ -108,24 +112,30 @@ func (d deployment) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstr
 	selector = strings.Trim(selector, " \n")
 	selector = string(yamlformat.Indent([]byte(selector), 4))
 
-	podLabels, err := yamlformat.Marshal(depl.Spec.Template.ObjectMeta.Labels, 8)
+	nameCamel := strcase.ToLowerCamel(name)
+	podMeta, err := pod.TemplateMeta(nameCamel, appMeta, depl.Spec.Template.ObjectMeta, values)
+	if err != nil {
+		return true, nil, err
+	}
+	podLabels, err := yamlformat.Marshal(podMeta["labels"], 8)
 	if err != nil {
 		return true, nil, err
 	}
+	podLabels = pod.RenderDefaults(podLabels)
 	podLabels += fmt.Sprintf("\n      {{- include \"%s.selectorLabels\" . | nindent 8 }}", appMeta.ChartName())
 
 	podAnnotations := ""
-	if len(depl.Spec.Template.ObjectMeta.Annotations) != 0 {
-		podAnnotations, err = yamlformat.Marshal(map[string]interface{}{"annotations": depl.Spec.Template.ObjectMeta.Annotations}, 6)
+	delete(podMeta, "labels")
+	if len(podMeta) != 0 {
+		podAnnotations, err = yamlformat.Marshal(podMeta, 6)
 		if err != nil {
 			return true, nil, err
 		}
 
-		podAnnotations = "\n" + podAnnotations
+		podAnnotations = "\n" + pod.RenderDefaults(podAnnotations)
 	}
 
-	nameCamel := strcase.ToLowerCamel(name)
-	specMap, podValues, err := pod.ProcessSpec(nameCamel, appMeta, depl.Spec.Template.Spec)
+	specMap, podValues, err := pod.ProcessSpec(nameCamel, appMeta, depl.Spec.Template.Spec, depl.Spec.Template.Labels)
 	if err != nil {
 		return true, nil, err
 	}
@@ -143,9 +153,16 @@ func (d deployment) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstr
 	}
 
 	spec = replaceSingleQuotes(spec)
+	spec = pod.RenderDefaults(spec)
+
+	schema := pod.Schema(nameCamel, depl.Spec.Template.Spec)
+	if replicas != "" {
+		schema.Add("minimum", 0, name, "replicas")
+	}
 
 	return true, &result{
 		values: values,
+		schema: schema,
 		data: struct {
 			Meta                 string
 			Replicas             string
@@ -297,6 +314,7 @@ type result struct {
 		Spec                 string
 	}
 	values helmify.Values
+	schema helmify.Schema
 }
 
 func (r *result) Filename() string {
@@ -307,6 +325,10 @@ func (r *result) Values() helmify.Values {
 	return r.values
 }
 
+func (r *result) Schema() helmify.Schema {
+	return r.schema
+}
+
 func (r *result) Write(writer io.Writer) error {
 	return deploymentTempl.Execute(writer, r.data)
 }
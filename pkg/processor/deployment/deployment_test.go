@@ -1,6 +1,7 @@
 package deployment
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/EdgeGamingGG/helmify/pkg/config"
@@ -140,6 +141,26 @@ func Test_deployment_Process(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, false, processed)
 	})
+	t.Run("replicas omitted when autoscaling enabled", func(t *testing.T) {
+		obj := internal.GenerateObj(strDepl)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(obj)
+		testMeta.Load(internal.GenerateObj(`apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: my-operator-controller-manager-hpa
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: my-operator-controller-manager
+  maxReplicas: 3`))
+		_, tmpl, err := testInstance.Process(testMeta, obj)
+		require.NoError(t, err)
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		assert.Contains(t, buf.String(), "  {{- if not .Values.myOperatorControllerManager.autoscaling.enabled }}\n  replicas: {{ .Values.myOperatorControllerManager.replicas }}\n  {{- end }}")
+	})
 }
 
 var singleQuotesTest = []struct {
@@ -264,23 +285,23 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 			expectedSpec: map[string]interface{}{
 				"containers": []interface{}{
 					map[string]interface{}{
-						"env": []interface{}{
-							map[string]interface{}{
-								"name":  "KUBERNETES_CLUSTER_DOMAIN",
-								"value": "{{ quote .Values.kubernetesClusterDomain }}",
-							},
-						},
-						"image": "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
-						"name":  "test-container",
+						"helmifyDefault.env": "test.testContainer.extraEnv",
+						"image":              "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
+						"name":               "test-container",
 						"volumeMounts": []interface{}{
 							"{{- toYaml .Values.test.testContainer.volumeMounts.hostData | nindent 10 }}",
+							map[string]interface{}{"helmifyDefaultEntries": "test.testContainer.extraVolumeMounts"},
 						},
-						"resources": map[string]interface{}{},
+						"resources":              map[string]interface{}{},
+						"helmifyDefault.envFrom": "test.testContainer.extraEnvFrom",
 					},
+					map[string]interface{}{"helmifyDefaultEntries": "test.extraContainers"},
 				},
 				"volumes": []interface{}{
-					"{{- toYaml .Values.test.volumes.hostData | nindent 8 }}",
+					"{{- tpl (toYaml .Values.test.volumes.hostData) $ | nindent 8 }}",
+					map[string]interface{}{"helmifyDefaultEntries": "test.extraVolumes"},
 				},
+				"helmifyDefault.initContainers": "test.extraInitContainers",
 			},
 			expectedPodValues: map[string]interface{}{
 				"test": map[string]interface{}{
@@ -295,6 +316,9 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 								"name":      "host-data",
 							},
 						},
+						"extraEnv":          []interface{}{},
+						"extraEnvFrom":      []interface{}{},
+						"extraVolumeMounts": []interface{}{},
 					},
 					"volumes": map[string]interface{}{
 						"hostData": map[string]interface{}{
@@ -305,6 +329,9 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 							},
 						},
 					},
+					"extraVolumes":        []interface{}{},
+					"extraContainers":     []interface{}{},
+					"extraInitContainers": []interface{}{},
 				},
 			},
 		},
@@ -344,23 +371,23 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 			expectedSpec: map[string]interface{}{
 				"containers": []interface{}{
 					map[string]interface{}{
-						"env": []interface{}{
-							map[string]interface{}{
-								"name":  "KUBERNETES_CLUSTER_DOMAIN",
-								"value": "{{ quote .Values.kubernetesClusterDomain }}",
-							},
-						},
-						"image": "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
-						"name":  "test-container",
+						"helmifyDefault.env": "test.testContainer.extraEnv",
+						"image":              "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
+						"name":               "test-container",
 						"volumeMounts": []interface{}{
 							"{{- toYaml .Values.test.testContainer.volumeMounts.csiVolume | nindent 10 }}",
+							map[string]interface{}{"helmifyDefaultEntries": "test.testContainer.extraVolumeMounts"},
 						},
-						"resources": map[string]interface{}{},
+						"resources":              map[string]interface{}{},
+						"helmifyDefault.envFrom": "test.testContainer.extraEnvFrom",
 					},
+					map[string]interface{}{"helmifyDefaultEntries": "test.extraContainers"},
 				},
 				"volumes": []interface{}{
-					"{{- toYaml .Values.test.volumes.csiVolume | nindent 8 }}",
+					"{{- tpl (toYaml .Values.test.volumes.csiVolume) $ | nindent 8 }}",
+					map[string]interface{}{"helmifyDefaultEntries": "test.extraVolumes"},
 				},
+				"helmifyDefault.initContainers": "test.extraInitContainers",
 			},
 			expectedPodValues: map[string]interface{}{
 				"test": map[string]interface{}{
@@ -375,6 +402,9 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 								"name":      "csi-volume",
 							},
 						},
+						"extraEnv":          []interface{}{},
+						"extraEnvFrom":      []interface{}{},
+						"extraVolumeMounts": []interface{}{},
 					},
 					"volumes": map[string]interface{}{
 						"csiVolume": map[string]interface{}{
@@ -387,6 +417,9 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 							},
 						},
 					},
+					"extraVolumes":        []interface{}{},
+					"extraContainers":     []interface{}{},
+					"extraInitContainers": []interface{}{},
 				},
 			},
 		},
@@ -399,7 +432,7 @@ func TestProcessSpec_VolumeMounts(t *testing.T) {
 			}
 			appMeta := metadata.New(*cfg)
 
-			got, gotPodValues, err := pod.ProcessSpec("test", appMeta, tt.spec.Template.Spec)
+			got, gotPodValues, err := pod.ProcessSpec("test", appMeta, tt.spec.Template.Spec, nil)
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedSpec, map[string]interface{}(got))
 			assert.Equal(t, tt.expectedPodValues, map[string]interface{}(gotPodValues))
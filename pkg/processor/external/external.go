@@ -0,0 +1,148 @@
+// Package external delegates k8s objects processing to an external executable.
+//
+// For every object helmify starts the executable, writes Request as JSON into its stdin and reads Response as JSON
+// from its stdout. The executable returns "processed": false for objects it is not able to process. Stderr output
+// is logged. Non-zero exit code fails chart generation.
+package external
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Request - sent to the executable stdin.
+type Request struct {
+	// Object - k8s object.
+	Object map[string]interface{} `json:"object"`
+	// AppMeta - app metadata computed by helmify.
+	AppMeta AppMeta `json:"appMeta"`
+}
+
+// AppMeta - subset of helmify.AppMetadata for the processed object.
+type AppMeta struct {
+	// ChartName - chart name used in helpers, e.g. {{ include "<chartName>.fullname" . }}.
+	ChartName string `json:"chartName"`
+	// Namespace - detected app namespace.
+	Namespace string `json:"namespace"`
+	// Name - object name with trimmed common prefix. Used as a default template filename.
+	Name string `json:"name"`
+	// ValuesKey - Name in camel case. Recommended as a values key.
+	ValuesKey string `json:"valuesKey"`
+	// TemplatedName - object name as helm template.
+	TemplatedName string `json:"templatedName"`
+	// Config - helmify config.
+	Config config.Config `json:"config"`
+}
+
+// Response - read from the executable stdout.
+type Response struct {
+	// Processed - false if executable is not able to process the object.
+	Processed bool `json:"processed"`
+	// Filename - template file name. Defaults to <Name>.yaml.
+	Filename string `json:"filename"`
+	// Template - helm template content.
+	Template string `json:"template"`
+	// Values - values used in template.
+	Values helmify.Values `json:"values"`
+}
+
+// New creates processor delegating objects to the external executable.
+func New(conf config.ExternalProcessor) helmify.Processor {
+	return &processor{conf: conf}
+}
+
+type processor struct {
+	conf config.ExternalProcessor
+}
+
+// Process passes object to the executable. Returns false if object kind is not configured for the processor
+// or the executable is not able to process the object.
+func (p processor) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if !p.matches(obj) {
+		return false, nil, nil
+	}
+	name := appMeta.TrimName(obj.GetName())
+	req, err := json.Marshal(Request{
+		Object: obj.Object,
+		AppMeta: AppMeta{
+			ChartName:     appMeta.ChartName(),
+			Namespace:     appMeta.Namespace(),
+			Name:          name,
+			ValuesKey:     strcase.ToLowerCamel(name),
+			TemplatedName: appMeta.TemplatedName(obj.GetName()),
+			Config:        appMeta.Config(),
+		},
+	})
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to marshal external processor request", err)
+	}
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	cmd := exec.Command(p.conf.Command, p.conf.Args...)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	log := logrus.WithFields(logrus.Fields{"Command": p.conf.Command, "Kind": obj.GetKind(), "Name": obj.GetName()})
+	if stderr.Len() != 0 {
+		log.Info(strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: external processor %s failed", err, p.conf.Command)
+	}
+	resp := Response{}
+	err = json.Unmarshal(stdout.Bytes(), &resp)
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to parse external processor %s response", err, p.conf.Command)
+	}
+	if !resp.Processed {
+		log.Debug("not processed by external processor")
+		return false, nil, nil
+	}
+	if resp.Filename == "" {
+		resp.Filename = name + ".yaml"
+	}
+	if resp.Values == nil {
+		resp.Values = helmify.Values{}
+	}
+	return true, &result{resp: resp}, nil
+}
+
+func (p processor) matches(obj *unstructured.Unstructured) bool {
+	if len(p.conf.Kinds) == 0 {
+		return true
+	}
+	gk := obj.GroupVersionKind().GroupKind()
+	for _, kind := range p.conf.Kinds {
+		if kind == gk.Kind || kind == gk.Group+"/"+gk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+type result struct {
+	resp Response
+}
+
+func (r *result) Filename() string {
+	return r.resp.Filename
+}
+
+func (r *result) Values() helmify.Values {
+	return r.resp.Values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte(strings.TrimRight(r.resp.Template, "\n")))
+	return err
+}
@@ -0,0 +1,94 @@
+package external
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const crdYaml = `apiVersion: example.com/v1
+kind: MyResource
+metadata:
+  name: my-operator-res
+spec:
+  size: 3`
+
+// TestMain runs test binary as an external processor if HELMIFY_TEST_PROCESSOR is set.
+func TestMain(m *testing.M) {
+	switch os.Getenv("HELMIFY_TEST_PROCESSOR") {
+	case "":
+		os.Exit(m.Run())
+	case "fail":
+		fmt.Fprint(os.Stderr, "boom")
+		os.Exit(1)
+	}
+	req := Request{}
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		os.Exit(2)
+	}
+	if req.Object["kind"] != "MyResource" {
+		_ = json.NewEncoder(os.Stdout).Encode(Response{})
+		os.Exit(0)
+	}
+	spec := req.Object["spec"].(map[string]interface{})
+	_ = json.NewEncoder(os.Stdout).Encode(Response{
+		Processed: true,
+		Template: fmt.Sprintf("apiVersion: example.com/v1\nkind: MyResource\nmetadata:\n  name: %s\nspec:\n  size: {{ .Values.%s.size }}\n",
+			req.AppMeta.TemplatedName, req.AppMeta.ValuesKey),
+		Values: helmify.Values{req.AppMeta.ValuesKey: map[string]interface{}{"size": spec["size"]}},
+	})
+	os.Exit(0)
+}
+
+func Test_processor_Process(t *testing.T) {
+	obj := internal.GenerateObj(crdYaml)
+	appMeta := metadata.New(config.Config{ChartName: "chart"})
+	appMeta.Load(obj)
+	appMeta.Load(internal.GenerateObj("apiVersion: v1\nkind: Service\nmetadata:\n  name: my-operator-svc"))
+	self, err := os.Executable()
+	require.NoError(t, err)
+
+	t.Run("processed", func(t *testing.T) {
+		t.Setenv("HELMIFY_TEST_PROCESSOR", "ok")
+		processed, tpl, err := New(config.ExternalProcessor{Command: self}).Process(appMeta, obj)
+		require.NoError(t, err)
+		assert.True(t, processed)
+		assert.Equal(t, "res.yaml", tpl.Filename())
+		assert.Equal(t, helmify.Values{"res": map[string]interface{}{"size": float64(3)}}, tpl.Values())
+		buf := bytes.Buffer{}
+		require.NoError(t, tpl.Write(&buf))
+		assert.Equal(t, "apiVersion: example.com/v1\nkind: MyResource\nmetadata:\n  name: {{ include \"chart.fullname\" . }}-res\nspec:\n  size: {{ .Values.res.size }}", buf.String())
+	})
+	t.Run("not processed", func(t *testing.T) {
+		t.Setenv("HELMIFY_TEST_PROCESSOR", "ok")
+		processed, _, err := New(config.ExternalProcessor{Command: self}).Process(appMeta, internal.TestNs)
+		require.NoError(t, err)
+		assert.False(t, processed)
+	})
+	t.Run("kind not matched", func(t *testing.T) {
+		t.Setenv("HELMIFY_TEST_PROCESSOR", "fail")
+		p := New(config.ExternalProcessor{Command: self, Kinds: []string{"Other", "example.com/Another"}})
+		processed, _, err := p.Process(appMeta, obj)
+		require.NoError(t, err)
+		assert.False(t, processed)
+		p = New(config.ExternalProcessor{Command: self, Kinds: []string{"example.com/MyResource"}})
+		processed, _, err = p.Process(appMeta, obj)
+		assert.True(t, processed)
+		assert.Error(t, err)
+	})
+	t.Run("failed", func(t *testing.T) {
+		t.Setenv("HELMIFY_TEST_PROCESSOR", "fail")
+		processed, _, err := New(config.ExternalProcessor{Command: self}).Process(appMeta, obj)
+		assert.True(t, processed)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/processor"
+	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const listenersTempl = `  listeners:
+  {{- tpl (toYaml .Values.%[1]s.listeners) $ | nindent 2 }}`
+
+var gatewayGK = schema.GroupKind{Group: group, Kind: "Gateway"}
+
+// New creates processor for Gateway API Gateway resource.
+func New() helmify.Processor {
+	return &gateway{}
+}
+
+type gateway struct{}
+
+// Process Gateway API Gateway object into template. Returns false if not capable of processing given resource type.
+func (r gateway) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind().GroupKind() != gatewayGK {
+		return false, nil, nil
+	}
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to get gateway spec", err)
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	values := helmify.Values{}
+	_, err = values.Add(true, nameCamel, "enabled")
+	if err != nil {
+		return true, nil, err
+	}
+
+	res := strings.Builder{}
+	res.WriteString(fmt.Sprintf("{{- if .Values.%s.enabled }}\n%s\nspec:", nameCamel, meta))
+
+	className, _ := spec["gatewayClassName"].(string)
+	delete(spec, "gatewayClassName")
+	classNameTpl, err := values.Add(className, nameCamel, "gatewayClassName")
+	if err != nil {
+		return true, nil, err
+	}
+	res.WriteString("\n  gatewayClassName: " + classNameTpl)
+
+	listeners, _ := spec["listeners"].([]interface{})
+	delete(spec, "listeners")
+	for _, listener := range listeners {
+		listenerMap, ok := listener.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		certRefs, _, _ := unstructured.NestedFieldNoCopy(listenerMap, "tls", "certificateRefs")
+		refs, _ := certRefs.([]interface{})
+		templateRefs(appMeta, refs, "Secret")
+	}
+	if listeners == nil {
+		listeners = []interface{}{}
+	}
+	err = unstructured.SetNestedSlice(values, listeners, nameCamel, "listeners")
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to set gateway listeners value", err)
+	}
+	res.WriteString("\n" + fmt.Sprintf(listenersTempl, nameCamel))
+
+	if len(spec) != 0 {
+		rest, err := yamlformat.Marshal(spec, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		res.WriteString("\n" + rest)
+	}
+	res.WriteString("\n{{- end }}")
+
+	return true, &result{
+		name:   name,
+		data:   res.String(),
+		values: values,
+	}, nil
+}
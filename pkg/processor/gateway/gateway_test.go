@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gatewayFullYaml = `apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: myapp-gateway
+  namespace: my-ns
+spec:
+  gatewayClassName: istio
+  listeners:
+  - name: https
+    hostname: myapp.example.com
+    port: 443
+    protocol: HTTPS
+    tls:
+      mode: Terminate
+      certificateRefs:
+      - name: myapp-tls
+  addresses:
+  - type: IPAddress
+    value: 10.0.0.1
+`
+	tlsSecretYaml = `apiVersion: v1
+kind: Secret
+metadata:
+  name: myapp-tls
+  namespace: my-ns
+`
+)
+
+func Test_gateway_Process(t *testing.T) {
+	testInstance := New()
+
+	t.Run("processed", func(t *testing.T) {
+		obj := internal.GenerateObj(gatewayFullYaml)
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		obj := internal.TestNs
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+	t.Run("listeners in values", func(t *testing.T) {
+		obj := internal.GenerateObj(gatewayFullYaml)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(obj)
+		testMeta.Load(internal.GenerateObj(tlsSecretYaml))
+		_, tmpl, err := testInstance.Process(testMeta, obj)
+		require.NoError(t, err)
+
+		assert.Equal(t, helmify.Values{
+			"gateway": map[string]interface{}{
+				"enabled":          true,
+				"gatewayClassName": "istio",
+				"listeners": []interface{}{
+					map[string]interface{}{
+						"name":     "https",
+						"hostname": "myapp.example.com",
+						"port":     int64(443),
+						"protocol": "HTTPS",
+						"tls": map[string]interface{}{
+							"mode": "Terminate",
+							"certificateRefs": []interface{}{
+								map[string]interface{}{"name": `{{ include "chart-name.fullname" . }}-tls`},
+							},
+						},
+					},
+				},
+			},
+		}, tmpl.Values())
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		assert.Equal(t, `{{- if .Values.gateway.enabled }}
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: {{ include "chart-name.fullname" . }}-gateway
+  labels:
+  {{- include "chart-name.labels" . | nindent 4 }}
+spec:
+  gatewayClassName: {{ .Values.gateway.gatewayClassName | quote }}
+  listeners:
+  {{- tpl (toYaml .Values.gateway.listeners) $ | nindent 2 }}
+  addresses:
+  - type: IPAddress
+    value: 10.0.0.1
+{{- end }}`, buf.String())
+	})
+}
@@ -0,0 +1,185 @@
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/processor"
+	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	group = "gateway.networking.k8s.io"
+
+	parentRefsTempl = `  {{- with .Values.%[1]s.parentRefs }}
+  parentRefs:
+  {{- tpl (toYaml .) $ | nindent 2 }}
+  {{- end }}`
+	hostnamesTempl = `  {{- with .Values.%[1]s.hostnames }}
+  hostnames:
+  {{- toYaml . | nindent 2 }}
+  {{- end }}`
+)
+
+var (
+	httpRouteGK = schema.GroupKind{Group: group, Kind: "HTTPRoute"}
+	grpcRouteGK = schema.GroupKind{Group: group, Kind: "GRPCRoute"}
+)
+
+// NewHTTPRoute creates processor for Gateway API HTTPRoute resource.
+func NewHTTPRoute() helmify.Processor {
+	return &route{gk: httpRouteGK}
+}
+
+// NewGRPCRoute creates processor for Gateway API GRPCRoute resource.
+func NewGRPCRoute() helmify.Processor {
+	return &route{gk: grpcRouteGK}
+}
+
+type route struct {
+	gk schema.GroupKind
+}
+
+// Process Gateway API route object into template. Returns false if not capable of processing given resource type.
+func (r route) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind().GroupKind() != r.gk {
+		return false, nil, nil
+	}
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to get %s spec", err, r.gk.Kind)
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	values := helmify.Values{}
+	_, err = values.Add(true, nameCamel, "enabled")
+	if err != nil {
+		return true, nil, err
+	}
+
+	res := strings.Builder{}
+	res.WriteString(fmt.Sprintf("{{- if .Values.%s.enabled }}\n%s\nspec:", nameCamel, meta))
+
+	parentRefs, _ := spec["parentRefs"].([]interface{})
+	delete(spec, "parentRefs")
+	templateRefs(appMeta, parentRefs, "Gateway", "Service")
+	err = unstructured.SetNestedSlice(values, parentRefs, nameCamel, "parentRefs")
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to set %s parentRefs value", err, r.gk.Kind)
+	}
+	res.WriteString("\n" + fmt.Sprintf(parentRefsTempl, nameCamel))
+
+	hostnames, _ := spec["hostnames"].([]interface{})
+	delete(spec, "hostnames")
+	if hostnames == nil {
+		hostnames = []interface{}{}
+	}
+	err = unstructured.SetNestedSlice(values, hostnames, nameCamel, "hostnames")
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to set %s hostnames value", err, r.gk.Kind)
+	}
+	res.WriteString("\n" + fmt.Sprintf(hostnamesTempl, nameCamel))
+
+	rules, _ := spec["rules"].([]interface{})
+	for _, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		processFilters(appMeta, ruleMap)
+		backendRefs, _ := ruleMap["backendRefs"].([]interface{})
+		templateRefs(appMeta, backendRefs, "Service")
+		for _, ref := range backendRefs {
+			if refMap, ok := ref.(map[string]interface{}); ok {
+				processFilters(appMeta, refMap)
+			}
+		}
+	}
+
+	if len(spec) != 0 {
+		rest, err := yamlformat.MarshalTemplated(spec, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		res.WriteString("\n" + rest)
+	}
+	res.WriteString("\n{{- end }}")
+
+	return true, &result{
+		name:   name,
+		data:   res.String(),
+		values: values,
+	}, nil
+}
+
+// processFilters templates backendRef of RequestMirror filters of the given rule or backendRef.
+func processFilters(appMeta helmify.AppMetadata, obj map[string]interface{}) {
+	filters, _ := obj["filters"].([]interface{})
+	for _, filter := range filters {
+		filterMap, ok := filter.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		mirror, found, _ := unstructured.NestedFieldNoCopy(filterMap, "requestMirror", "backendRef")
+		if !found {
+			continue
+		}
+		templateRefs(appMeta, []interface{}{mirror}, "Service")
+	}
+}
+
+// templateRefs replaces names of the references to app objects of the given kinds with templated names.
+// Kind is optional for Gateway API references, so references without kind are templated as well.
+func templateRefs(appMeta helmify.AppMetadata, refs []interface{}, kinds ...string) {
+	for _, ref := range refs {
+		refMap, ok := ref.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := refMap["kind"].(string)
+		if kind != "" && !contains(kinds, kind) {
+			continue
+		}
+		if name, ok := refMap["name"].(string); ok {
+			refMap["name"] = appMeta.TemplatedName(name)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+type result struct {
+	name   string
+	data   string
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte(r.data))
+	return err
+}
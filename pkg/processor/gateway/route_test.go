@@ -0,0 +1,178 @@
+package gateway
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	httpRouteYaml = `apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: myapp-route
+  namespace: my-ns
+spec:
+  parentRefs:
+  - name: myapp-gateway
+  - kind: Gateway
+    name: shared-gateway
+    namespace: infra
+  hostnames:
+  - myapp.example.com
+  rules:
+  - matches:
+    - path:
+        type: PathPrefix
+        value: /api
+    filters:
+    - type: RequestMirror
+      requestMirror:
+        backendRef:
+          name: myapp-shadow
+          port: 8080
+    backendRefs:
+    - name: myapp-service
+      port: 8080
+    - kind: ExternalBackend
+      name: myapp-service
+`
+	svcYaml = `apiVersion: v1
+kind: Service
+metadata:
+  name: myapp-service
+  namespace: my-ns
+`
+	shadowSvcYaml = `apiVersion: v1
+kind: Service
+metadata:
+  name: myapp-shadow
+  namespace: my-ns
+`
+	gatewayYaml = `apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: myapp-gateway
+  namespace: my-ns
+`
+)
+
+func Test_route_Process(t *testing.T) {
+	testInstance := NewHTTPRoute()
+
+	t.Run("processed", func(t *testing.T) {
+		obj := internal.GenerateObj(httpRouteYaml)
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		obj := internal.TestNs
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+	t.Run("skipped grpc route", func(t *testing.T) {
+		obj := internal.GenerateObj(httpRouteYaml)
+		processed, _, err := NewGRPCRoute().Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+	t.Run("refs templated", func(t *testing.T) {
+		obj := internal.GenerateObj(httpRouteYaml)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(obj)
+		testMeta.Load(internal.GenerateObj(svcYaml))
+		testMeta.Load(internal.GenerateObj(shadowSvcYaml))
+		testMeta.Load(internal.GenerateObj(gatewayYaml))
+		_, tmpl, err := testInstance.Process(testMeta, obj)
+		require.NoError(t, err)
+
+		assert.Equal(t, helmify.Values{
+			"route": map[string]interface{}{
+				"enabled": true,
+				"parentRefs": []interface{}{
+					map[string]interface{}{"name": `{{ include "chart-name.fullname" . }}-gateway`},
+					map[string]interface{}{"kind": "Gateway", "name": "shared-gateway", "namespace": "infra"},
+				},
+				"hostnames": []interface{}{"myapp.example.com"},
+			},
+		}, tmpl.Values())
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		assert.Equal(t, `{{- if .Values.route.enabled }}
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: {{ include "chart-name.fullname" . }}-route
+  labels:
+  {{- include "chart-name.labels" . | nindent 4 }}
+spec:
+  {{- with .Values.route.parentRefs }}
+  parentRefs:
+  {{- tpl (toYaml .) $ | nindent 2 }}
+  {{- end }}
+  {{- with .Values.route.hostnames }}
+  hostnames:
+  {{- toYaml . | nindent 2 }}
+  {{- end }}
+  rules:
+  - backendRefs:
+    - name: {{ include "chart-name.fullname" . }}-service
+      port: 8080
+    - kind: ExternalBackend
+      name: myapp-service
+    filters:
+    - requestMirror:
+        backendRef:
+          name: {{ include "chart-name.fullname" . }}-shadow
+          port: 8080
+      type: RequestMirror
+    matches:
+    - path:
+        type: PathPrefix
+        value: /api
+{{- end }}`, buf.String())
+	})
+	t.Run("quoted match values kept", func(t *testing.T) {
+		obj := internal.GenerateObj(`apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: myapp-route
+spec:
+  rules:
+  - matches:
+    - path:
+        type: RegularExpression
+        value: "/api/*"
+      headers:
+      - name: x-version
+        value: "2"
+      queryParams:
+      - name: q
+        value: "it's"
+    backendRefs:
+    - name: myapp-service
+      port: 8080
+`)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(obj)
+		testMeta.Load(internal.GenerateObj(svcYaml))
+		_, tmpl, err := testInstance.Process(testMeta, obj)
+		require.NoError(t, err)
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		assert.Contains(t, buf.String(), `- name: {{ include "chart-name.fullname" . }}-service`)
+		assert.Contains(t, buf.String(), "value: /api/*")
+		assert.Contains(t, buf.String(), `value: "2"`)
+		assert.Contains(t, buf.String(), "value: it's")
+	})
+}
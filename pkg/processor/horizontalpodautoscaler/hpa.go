@@ -0,0 +1,165 @@
+package horizontalpodautoscaler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/processor"
+	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var hpaTempl, _ = template.New("hpa").Parse(
+	`{{ printf "{{- if .Values.%s.autoscaling.enabled }}" .Target }}
+{{ .Meta }}
+spec:
+{{ .ScaleTargetRef }}
+{{- if .MinReplicas }}
+{{ .MinReplicas }}
+{{- end }}
+{{ .MaxReplicas }}
+{{- if .Metrics }}
+{{ .Metrics }}
+{{- end }}
+{{- if .Behavior }}
+{{ .Behavior }}
+{{- end }}
+{{ "{{- end }}" }}`)
+
+var hpaGVC = schema.GroupVersionKind{
+	Group:   "autoscaling",
+	Version: "v2",
+	Kind:    "HorizontalPodAutoscaler",
+}
+
+// New creates processor for k8s HorizontalPodAutoscaler resource.
+func New() helmify.Processor {
+	return &hpa{}
+}
+
+type hpa struct{}
+
+// Process k8s HorizontalPodAutoscaler object into template. Returns false if not capable of processing given resource type.
+func (r hpa) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != hpaGVC {
+		return false, nil, nil
+	}
+	autoscaler := autoscalingv2.HorizontalPodAutoscaler{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &autoscaler)
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to cast to hpa", err)
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	spec := autoscaler.Spec
+	values := helmify.Values{}
+	// autoscaling is toggled in the target values, so the target replicas are templated by the same key.
+	// Scale target name may be already templated by references rewriting.
+	targetName := strings.TrimPrefix(spec.ScaleTargetRef.Name, fmt.Sprintf(`{{ include "%s.fullname" . }}-`, appMeta.ChartName()))
+	target := strcase.ToLowerCamel(appMeta.TrimName(targetName))
+	_, err = values.Add(true, target, "autoscaling", "enabled")
+	if err != nil {
+		return true, nil, err
+	}
+
+	scaleTargetRefUnstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&spec.ScaleTargetRef)
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to convert hpa scaleTargetRef to unstructured", err)
+	}
+	scaleTargetRefUnstr["name"] = appMeta.TemplatedName(spec.ScaleTargetRef.Name)
+	scaleTargetRef, err := yamlformat.MarshalTemplated(map[string]interface{}{"scaleTargetRef": scaleTargetRefUnstr}, 2)
+	if err != nil {
+		return true, nil, err
+	}
+
+	var minReplicas, metrics, behavior string
+	if spec.MinReplicas != nil {
+		minReplicas, err = values.Add(*spec.MinReplicas, nameCamel, "minReplicas")
+		if err != nil {
+			return true, nil, err
+		}
+		minReplicas = "  minReplicas: " + minReplicas
+	}
+	maxReplicas, err := values.Add(spec.MaxReplicas, nameCamel, "maxReplicas")
+	if err != nil {
+		return true, nil, err
+	}
+	maxReplicas = "  maxReplicas: " + maxReplicas
+
+	if len(spec.Metrics) != 0 {
+		metricsUnstr := make([]interface{}, len(spec.Metrics))
+		for i := range spec.Metrics {
+			metricsUnstr[i], err = runtime.DefaultUnstructuredConverter.ToUnstructured(&spec.Metrics[i])
+			if err != nil {
+				return true, nil, fmt.Errorf("%w: unable to convert hpa metric to unstructured", err)
+			}
+		}
+		metrics, err = values.Add(metricsUnstr, nameCamel, "metrics")
+		if err != nil {
+			return true, nil, err
+		}
+		metrics = "  metrics: " + metrics
+	}
+
+	if spec.Behavior != nil {
+		behavior, err = yamlformat.Marshal(map[string]interface{}{"behavior": spec.Behavior}, 2)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+
+	return true, &result{
+		name: name,
+		data: hpaData{
+			Meta:           meta,
+			Target:         target,
+			ScaleTargetRef: scaleTargetRef,
+			MinReplicas:    minReplicas,
+			MaxReplicas:    maxReplicas,
+			Metrics:        metrics,
+			Behavior:       behavior,
+		},
+		values: values,
+	}, nil
+}
+
+// hpaData - hpaTempl parameters.
+type hpaData struct {
+	Meta           string
+	Target         string
+	ScaleTargetRef string
+	MinReplicas    string
+	MaxReplicas    string
+	Metrics        string
+	Behavior       string
+}
+
+type result struct {
+	name   string
+	data   hpaData
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	return hpaTempl.Execute(writer, r.data)
+}
@@ -0,0 +1,119 @@
+package horizontalpodautoscaler
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const hpaYaml = `apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: my-operator-controller-manager-hpa
+  namespace: my-operator-system
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: my-operator-controller-manager
+  minReplicas: 1
+  maxReplicas: 5
+  metrics:
+  - type: Resource
+    resource:
+      name: cpu
+      target:
+        type: Utilization
+        averageUtilization: 80`
+
+func Test_hpa_Process(t *testing.T) {
+	var testInstance hpa
+
+	t.Run("processed", func(t *testing.T) {
+		obj := internal.GenerateObj(hpaYaml)
+		processed, tt, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+		assert.Equal(t, helmify.Values{
+			"myOperatorControllerManager": map[string]interface{}{
+				"autoscaling": map[string]interface{}{"enabled": true},
+			},
+			"myOperatorControllerManagerHpa": map[string]interface{}{
+				"minReplicas": int64(1),
+				"maxReplicas": int64(5),
+				"metrics": []interface{}{
+					map[string]interface{}{
+						"type": "Resource",
+						"resource": map[string]interface{}{
+							"name": "cpu",
+							"target": map[string]interface{}{
+								"type":               "Utilization",
+								"averageUtilization": int64(80),
+							},
+						},
+					},
+				},
+			},
+		}, tt.Values())
+	})
+	t.Run("templated scale target", func(t *testing.T) {
+		obj := internal.GenerateObj(hpaYaml)
+		deployment := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-operator-controller-manager
+  namespace: my-operator-system`)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(obj)
+		testMeta.Load(deployment)
+		assert.True(t, testMeta.Autoscaled("Deployment", "my-operator-controller-manager"))
+
+		_, tt, err := testInstance.Process(testMeta, obj)
+		assert.NoError(t, err)
+		buf := bytes.Buffer{}
+		assert.NoError(t, tt.Write(&buf))
+		assert.Contains(t, buf.String(), `{{- if .Values.myOperatorControllerManager.autoscaling.enabled }}`)
+		assert.Contains(t, buf.String(), `name: {{ include "chart-name.fullname" . }}-my-operator-controller-manager`)
+		assert.Contains(t, buf.String(), `maxReplicas: {{ .Values.hpa.maxReplicas }}`)
+	})
+	t.Run("toggled per scale target", func(t *testing.T) {
+		obj := internal.GenerateObj(hpaYaml)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(obj)
+		testMeta.Load(internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-operator-controller-manager`))
+		testMeta.Load(internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-operator-web`))
+		// scale target name templated by references rewriting
+		err := unstructured.SetNestedField(obj.Object, `{{ include "chart-name.fullname" . }}-controller-manager`, "spec", "scaleTargetRef", "name")
+		require.NoError(t, err)
+
+		_, tt, err := testInstance.Process(testMeta, obj)
+		require.NoError(t, err)
+		enabled, _, _ := unstructured.NestedBool(tt.Values(), "controllerManager", "autoscaling", "enabled")
+		assert.True(t, enabled)
+		assert.NotContains(t, tt.Values(), "web")
+		buf := bytes.Buffer{}
+		require.NoError(t, tt.Write(&buf))
+		assert.Contains(t, buf.String(), `{{- if .Values.controllerManager.autoscaling.enabled }}`)
+		assert.Contains(t, buf.String(), `name: {{ include "chart-name.fullname" . }}-controller-manager`)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		obj := internal.TestNs
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
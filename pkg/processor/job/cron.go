@@ -26,6 +26,9 @@ var cronGVC = schema.GroupVersionKind{
 	Kind:    "CronJob",
 }
 
+// cronPodSpecIndent - indentation of the pod spec fields in CronJob template.
+const cronPodSpecIndent = 10
+
 // NewCron creates processor for k8s CronJob resource.
 func NewCron() helmify.Processor {
 	return &cron{}
@@ -105,7 +108,7 @@ func (p cron) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructure
 	}
 
 	// process job pod template:
-	podSpecMap, podValues, err := pod.ProcessSpec(nameCamelCase, appMeta, jobObj.Spec.JobTemplate.Spec.Template.Spec)
+	podSpecMap, podValues, err := pod.ProcessSpec(nameCamelCase, appMeta, jobObj.Spec.JobTemplate.Spec.Template.Spec, nil)
 	if err != nil {
 		return true, nil, err
 	}
@@ -114,16 +117,36 @@ func (p cron) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructure
 		return true, nil, err
 	}
 
+	// pod spec fields are placed deeper than in Deployment
+	pod.ShiftIndent(podSpecMap, cronPodSpecIndent-pod.SpecIndent)
 	err = unstructured.SetNestedMap(specMap, podSpecMap, "jobTemplate", "spec", "template", "spec")
 	if err != nil {
 		return true, nil, fmt.Errorf("%w: unable to template job spec", err)
 	}
+	if appMeta.Config().GenerateDefaults {
+		podMeta, _, err := unstructured.NestedMap(specMap, "jobTemplate", "spec", "template", "metadata")
+		if err != nil {
+			return true, nil, fmt.Errorf("%w: unable to get pod template metadata", err)
+		}
+		if podMeta == nil {
+			podMeta = map[string]interface{}{}
+		}
+		err = pod.ProcessMetaDefaults(nameCamelCase, appMeta, podMeta, values)
+		if err != nil {
+			return true, nil, err
+		}
+		err = unstructured.SetNestedMap(specMap, podMeta, "jobTemplate", "spec", "template", "metadata")
+		if err != nil {
+			return true, nil, fmt.Errorf("%w: unable to set pod template metadata", err)
+		}
+	}
 
 	specStr, err := yamlformat.Marshal(map[string]interface{}{"spec": specMap}, 0)
 	if err != nil {
 		return true, nil, err
 	}
 	specStr = strings.ReplaceAll(specStr, "'", "")
+	specStr = pod.RenderDefaults(specStr)
 
 	return true, &resultCron{
 		name: name + ".yaml",
@@ -132,6 +155,7 @@ func (p cron) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructure
 			Spec string
 		}{Meta: meta, Spec: specStr},
 		values: values,
+		schema: pod.Schema(nameCamelCase, jobObj.Spec.JobTemplate.Spec.Template.Spec),
 	}, nil
 }
 
@@ -142,6 +166,7 @@ type resultCron struct {
 		Spec string
 	}
 	values helmify.Values
+	schema helmify.Schema
 }
 
 func (r *resultCron) Filename() string {
@@ -152,6 +177,10 @@ func (r *resultCron) Values() helmify.Values {
 	return r.values
 }
 
+func (r *resultCron) Schema() helmify.Schema {
+	return r.schema
+}
+
 func (r *resultCron) Write(writer io.Writer) error {
 	return cronTempl.Execute(writer, r.data)
 }
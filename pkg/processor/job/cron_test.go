@@ -1,10 +1,14 @@
 package job
 
 import (
+	"bytes"
+	"testing"
+
 	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/metadata"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -45,3 +49,19 @@ func Test_Cron_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_Cron_Process_defaults(t *testing.T) {
+	var testInstance cron
+	obj := internal.GenerateObj(strCron)
+	appMeta := metadata.New(config.Config{ChartName: "chart", GenerateDefaults: true})
+	appMeta.Load(obj)
+	_, tmpl, err := testInstance.Process(appMeta, obj)
+	require.NoError(t, err)
+	buf := bytes.Buffer{}
+	require.NoError(t, tmpl.Write(&buf))
+	out := buf.String()
+	assert.NotContains(t, out, "helmifyDefault")
+	assert.Contains(t, out, "          {{- with .Values.cronJob.nodeSelector }}\n          nodeSelector:\n            {{- tpl (toYaml .) $ | nindent 12 }}\n          {{- end }}")
+	assert.Contains(t, out, "          {{- with .Values.cronJob.podLabels }}\n          labels:\n            {{- tpl (toYaml .) $ | nindent 12 }}\n          {{- end }}")
+	assert.Contains(t, tmpl.Values()["cronJob"], "priorityClassName")
+}
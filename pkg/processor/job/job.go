@@ -104,7 +104,7 @@ func (p job) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 		}
 	}
 	// process job pod template:
-	podSpecMap, podValues, err := pod.ProcessSpec(nameCamelCase, appMeta, jobObj.Spec.Template.Spec)
+	podSpecMap, podValues, err := pod.ProcessSpec(nameCamelCase, appMeta, jobObj.Spec.Template.Spec, nil)
 	if err != nil {
 		return true, nil, err
 	}
@@ -117,12 +117,30 @@ func (p job) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 	if err != nil {
 		return true, nil, fmt.Errorf("%w: unable to template job spec", err)
 	}
+	if appMeta.Config().GenerateDefaults {
+		podMeta, _, err := unstructured.NestedMap(specMap, "template", "metadata")
+		if err != nil {
+			return true, nil, fmt.Errorf("%w: unable to get pod template metadata", err)
+		}
+		if podMeta == nil {
+			podMeta = map[string]interface{}{}
+		}
+		err = pod.ProcessMetaDefaults(nameCamelCase, appMeta, podMeta, values)
+		if err != nil {
+			return true, nil, err
+		}
+		err = unstructured.SetNestedMap(specMap, podMeta, "template", "metadata")
+		if err != nil {
+			return true, nil, fmt.Errorf("%w: unable to set pod template metadata", err)
+		}
+	}
 
 	specStr, err := yamlformat.Marshal(map[string]interface{}{"spec": specMap}, 0)
 	if err != nil {
 		return true, nil, err
 	}
 	specStr = strings.ReplaceAll(specStr, "'", "")
+	specStr = pod.RenderDefaults(specStr)
 
 	return true, &result{
 		name: name + ".yaml",
@@ -131,6 +149,7 @@ func (p job) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 			Spec string
 		}{Meta: meta, Spec: specStr},
 		values: values,
+		schema: pod.Schema(nameCamelCase, jobObj.Spec.Template.Spec),
 	}, nil
 }
 
@@ -141,6 +160,7 @@ type result struct {
 		Spec string
 	}
 	values helmify.Values
+	schema helmify.Schema
 }
 
 func (r *result) Filename() string {
@@ -151,6 +171,10 @@ func (r *result) Values() helmify.Values {
 	return r.values
 }
 
+func (r *result) Schema() helmify.Schema {
+	return r.schema
+}
+
 func (r *result) Write(writer io.Writer) error {
 	return jobTempl.Execute(writer, r.data)
 }
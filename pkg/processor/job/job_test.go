@@ -1,10 +1,14 @@
 package job
 
 import (
+	"bytes"
+	"testing"
+
 	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/metadata"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -39,3 +43,40 @@ func Test_configMap_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_job_Process_defaults(t *testing.T) {
+	var testInstance job
+	process := func(objYaml string) string {
+		obj := internal.GenerateObj(objYaml)
+		appMeta := metadata.New(config.Config{ChartName: "chart", GenerateDefaults: true})
+		appMeta.Load(obj)
+		_, tmpl, err := testInstance.Process(appMeta, obj)
+		require.NoError(t, err)
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		return buf.String()
+	}
+
+	t.Run("empty pod labels", func(t *testing.T) {
+		out := process(strJob)
+		assert.NotContains(t, out, "helmifyDefault")
+		assert.Contains(t, out, "      {{- with .Values.batchJob.podLabels }}\n      labels:\n        {{- tpl (toYaml .) $ | nindent 8 }}\n      {{- end }}")
+	})
+	t.Run("pod labels", func(t *testing.T) {
+		out := process(`apiVersion: batch/v1
+kind: Job
+metadata:
+  name: batch-job
+spec:
+  template:
+    metadata:
+      labels:
+        app: pi
+    spec:
+      containers:
+        - name: pi
+          image: perl:5.34.0
+      restartPolicy: Never`)
+		assert.Contains(t, out, "      labels:\n        app: pi\n        {{- with .Values.batchJob.podLabels }}\n        {{- tpl (toYaml .) $ | nindent 8 }}\n        {{- end }}")
+	})
+}
@@ -8,6 +8,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
 )
@@ -82,10 +83,17 @@ func ProcessObjMeta(appMeta helmify.AppMetadata, obj *unstructured.Unstructured,
 		}
 	}
 
-	if (obj.GetNamespace() != "") && (appMeta.Config().PreserveNs) {
-		namespace, err = yamlformat.Marshal(map[string]interface{}{"namespace": obj.GetNamespace()}, 2)
-		if err != nil {
-			return "", err
+	switch appMeta.Config().Namespaces() {
+	case config.NamespacePreserve:
+		if obj.GetNamespace() != "" {
+			namespace, err = yamlformat.Marshal(map[string]interface{}{"namespace": obj.GetNamespace()}, 2)
+			if err != nil {
+				return "", err
+			}
+		}
+	case config.NamespaceRelease, config.NamespaceValues:
+		if namespaced(obj) {
+			namespace = "  namespace: " + appMeta.TemplatedNamespace(obj.GetNamespace())
 		}
 	}
 
@@ -113,3 +121,48 @@ func ProcessObjMeta(appMeta helmify.AppMetadata, obj *unstructured.Unstructured,
 	metaStr = strings.ReplaceAll(metaStr, "\n\n", "\n")
 	return metaStr, nil
 }
+
+// namespacedKinds - well-known namespaced kinds: built-in ones and custom resources processed by helmify.
+// Scope of other kinds is unknown, e.g. cluster-scoped ClusterIssuer or ClusterPolicy custom resources.
+var namespacedKinds = map[string]bool{
+	"Pod":                     true,
+	"PodTemplate":             true,
+	"ReplicationController":   true,
+	"Service":                 true,
+	"Endpoints":               true,
+	"EndpointSlice":           true,
+	"ConfigMap":               true,
+	"Secret":                  true,
+	"ServiceAccount":          true,
+	"PersistentVolumeClaim":   true,
+	"LimitRange":              true,
+	"ResourceQuota":           true,
+	"Event":                   true,
+	"Deployment":              true,
+	"DaemonSet":               true,
+	"StatefulSet":             true,
+	"ReplicaSet":              true,
+	"ControllerRevision":      true,
+	"Job":                     true,
+	"CronJob":                 true,
+	"HorizontalPodAutoscaler": true,
+	"PodDisruptionBudget":     true,
+	"Ingress":                 true,
+	"NetworkPolicy":           true,
+	"Role":                    true,
+	"RoleBinding":             true,
+	"Lease":                   true,
+	"CSIStorageCapacity":      true,
+	"Certificate":             true,
+	"Issuer":                  true,
+	"Rollout":                 true,
+	"Gateway":                 true,
+	"HTTPRoute":               true,
+	"GRPCRoute":               true,
+}
+
+// namespaced returns true if object has a namespace or is of a well-known namespaced kind.
+// Objects of unknown scope without namespace are not templated with one.
+func namespaced(obj *unstructured.Unstructured) bool {
+	return obj.GetNamespace() != "" || namespacedKinds[obj.GetKind()]
+}
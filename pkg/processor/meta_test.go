@@ -17,3 +17,62 @@ func TestProcessObjMeta(t *testing.T) {
 	assert.Contains(t, res, "chart-name.labels")
 	assert.Contains(t, res, "chart-name.fullname")
 }
+
+func TestProcessObjMeta_namespace(t *testing.T) {
+	const cmYaml = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+  namespace: monitoring`
+	const roleYaml = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: my-role`
+	const svcYaml = `apiVersion: v1
+kind: Service
+metadata:
+  name: my-svc`
+	const issuerYaml = `apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: my-issuer`
+	const policyYaml = `apiVersion: kyverno.io/v1
+kind: Policy
+metadata:
+  name: my-policy`
+	const nsPolicyYaml = `apiVersion: kyverno.io/v1
+kind: Policy
+metadata:
+  name: my-policy
+  namespace: monitoring`
+	tests := []struct {
+		mode     config.NamespaceMode
+		obj      string
+		expected string
+	}{
+		{mode: config.NamespaceOmit, obj: cmYaml, expected: ""},
+		{mode: config.NamespacePreserve, obj: cmYaml, expected: "namespace: monitoring"},
+		{mode: config.NamespaceRelease, obj: cmYaml, expected: "namespace: {{ .Release.Namespace }}"},
+		{mode: config.NamespaceRelease, obj: svcYaml, expected: "namespace: {{ .Release.Namespace }}"},
+		{mode: config.NamespaceRelease, obj: roleYaml, expected: ""},
+		{mode: config.NamespaceValues, obj: cmYaml, expected: "namespace: {{ .Values.namespaces.monitoring }}"},
+		{mode: config.NamespaceValues, obj: svcYaml, expected: "namespace: {{ .Release.Namespace }}"},
+		{mode: config.NamespaceValues, obj: roleYaml, expected: ""},
+		{mode: config.NamespaceRelease, obj: issuerYaml, expected: ""},
+		{mode: config.NamespaceRelease, obj: policyYaml, expected: ""},
+		{mode: config.NamespaceRelease, obj: nsPolicyYaml, expected: "namespace: {{ .Release.Namespace }}"},
+		{mode: config.NamespaceValues, obj: policyYaml, expected: ""},
+	}
+	for _, tt := range tests {
+		obj := internal.GenerateObj(tt.obj)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name", NamespaceMode: tt.mode})
+		testMeta.Load(obj)
+		res, err := ProcessObjMeta(testMeta, obj)
+		assert.NoError(t, err)
+		if tt.expected == "" {
+			assert.NotContains(t, res, "namespace:", "%s %s", tt.mode, obj.GetKind())
+		} else {
+			assert.Contains(t, res, tt.expected, "%s %s", tt.mode, obj.GetKind())
+		}
+	}
+}
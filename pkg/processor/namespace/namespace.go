@@ -0,0 +1,110 @@
+package namespace
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var nsGVK = schema.GroupVersionKind{
+	Group:   "",
+	Version: "v1",
+	Kind:    "Namespace",
+}
+
+const nsTempl = `{{- if .Values.createNamespace }}
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %[1]s
+  labels:
+%[2]s  {{- include "%[3]s.labels" . | nindent 4 }}
+%[4]s{{- end }}`
+
+const (
+	// metadataKey - values key for namespace labels and annotations. Namespace names may be the same as workload
+	// names, so namespaces are kept apart from workload values.
+	metadataKey      = "namespaceMetadata"
+	labelsTempl      = "  {{- with .Values.%[1]s.%[2]s.labels }}\n  {{- toYaml . | nindent 4 }}\n  {{- end }}\n"
+	annotationsTempl = "  {{- with .Values.%[1]s.%[2]s.annotations }}\n  annotations:\n    {{- toYaml . | nindent 4 }}\n  {{- end }}\n"
+)
+
+// New creates processor for k8s Namespace resource. Namespaces are processed only if Config.CreateNamespace is set,
+// otherwise they are skipped by the default processor.
+func New() helmify.Processor {
+	return &namespace{}
+}
+
+type namespace struct{}
+
+// Process k8s Namespace object into template guarded by createNamespace value.
+// Labels and annotations are moved into values under namespaceMetadata.<name>. Returns false if not capable of processing given resource type.
+func (n namespace) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != nsGVK || !appMeta.Config().CreateNamespace {
+		return false, nil, nil
+	}
+	name := obj.GetName()
+	if appMeta.Config().Namespaces() == config.NamespaceValues {
+		name = appMeta.TemplatedNamespace(name)
+	}
+	nameCamel := strcase.ToLowerCamel(obj.GetName())
+	values := helmify.Values{}
+	_, err := values.Add(true, "createNamespace")
+	if err != nil {
+		return true, nil, err
+	}
+
+	labels, annotations := "", ""
+	l := obj.GetLabels()
+	// provided by Helm or by k8s
+	delete(l, "app.kubernetes.io/name")
+	delete(l, "app.kubernetes.io/instance")
+	delete(l, "app.kubernetes.io/version")
+	delete(l, "app.kubernetes.io/managed-by")
+	delete(l, "helm.sh/chart")
+	delete(l, "kubernetes.io/metadata.name")
+	if len(l) != 0 {
+		err = unstructured.SetNestedStringMap(values, l, metadataKey, nameCamel, "labels")
+		if err != nil {
+			return true, nil, fmt.Errorf("%w: unable to set namespace labels value", err)
+		}
+		labels = fmt.Sprintf(labelsTempl, metadataKey, nameCamel)
+	}
+	if a := obj.GetAnnotations(); len(a) != 0 {
+		err = unstructured.SetNestedStringMap(values, a, metadataKey, nameCamel, "annotations")
+		if err != nil {
+			return true, nil, fmt.Errorf("%w: unable to set namespace annotations value", err)
+		}
+		annotations = fmt.Sprintf(annotationsTempl, metadataKey, nameCamel)
+	}
+	return true, &result{
+		name:   appMeta.TrimName(obj.GetName()),
+		data:   []byte(fmt.Sprintf(nsTempl, name, labels, appMeta.ChartName(), annotations)),
+		values: values,
+	}, nil
+}
+
+type result struct {
+	name   string
+	data   []byte
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return strings.TrimSuffix(r.name, "-namespace") + "-namespace.yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
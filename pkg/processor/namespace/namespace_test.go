@@ -0,0 +1,121 @@
+package namespace
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const nsYaml = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: my-operator-system
+  labels:
+    kubernetes.io/metadata.name: my-operator-system
+    pod-security.kubernetes.io/enforce: restricted
+  annotations:
+    owner: platform`
+
+const deploymentYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-operator-controller-manager
+  namespace: my-operator-system`
+
+func newTestMeta(conf config.Config) *metadata.Service {
+	conf.ChartName = "chart-name"
+	testMeta := metadata.New(conf)
+	testMeta.Load(internal.GenerateObj(nsYaml))
+	testMeta.Load(internal.GenerateObj(deploymentYaml))
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-webhook
+  namespace: my-operator-system`))
+	return testMeta
+}
+
+func Test_namespace_Process(t *testing.T) {
+	var testInstance namespace
+
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := testInstance.Process(newTestMeta(config.Config{CreateNamespace: true}), internal.GenerateObj(deploymentYaml))
+		require.NoError(t, err)
+		assert.False(t, processed)
+	})
+	t.Run("skipped without createNamespace", func(t *testing.T) {
+		processed, _, err := testInstance.Process(newTestMeta(config.Config{}), internal.GenerateObj(nsYaml))
+		require.NoError(t, err)
+		assert.False(t, processed)
+	})
+	t.Run("processed", func(t *testing.T) {
+		processed, tmpl, err := testInstance.Process(newTestMeta(config.Config{CreateNamespace: true}), internal.GenerateObj(nsYaml))
+		require.NoError(t, err)
+		assert.True(t, processed)
+		assert.Equal(t, "system-namespace.yaml", tmpl.Filename())
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		out := buf.String()
+		assert.Contains(t, out, "{{- if .Values.createNamespace }}\n")
+		assert.Contains(t, out, "  name: my-operator-system\n")
+		assert.Contains(t, out, "{{- with .Values.namespaceMetadata.myOperatorSystem.labels }}")
+		assert.Contains(t, out, `{{- include "chart-name.labels" . | nindent 4 }}`)
+		assert.Contains(t, out, "{{- with .Values.namespaceMetadata.myOperatorSystem.annotations }}")
+
+		values := tmpl.Values()
+		assert.Equal(t, true, values["createNamespace"])
+		labels, _, _ := unstructured.NestedStringMap(values, "namespaceMetadata", "myOperatorSystem", "labels")
+		assert.Equal(t, map[string]string{"pod-security.kubernetes.io/enforce": "restricted"}, labels)
+		annotations, _, _ := unstructured.NestedStringMap(values, "namespaceMetadata", "myOperatorSystem", "annotations")
+		assert.Equal(t, map[string]string{"owner": "platform"}, annotations)
+	})
+	t.Run("no labels and annotations", func(t *testing.T) {
+		obj := internal.GenerateObj(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: my-operator-system`)
+		_, tmpl, err := testInstance.Process(newTestMeta(config.Config{CreateNamespace: true}), obj)
+		require.NoError(t, err)
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		assert.NotContains(t, buf.String(), ".Values.namespaceMetadata")
+		assert.NotContains(t, tmpl.Values(), "namespaceMetadata")
+	})
+	t.Run("values namespace mode", func(t *testing.T) {
+		testMeta := newTestMeta(config.Config{CreateNamespace: true, NamespaceMode: config.NamespaceValues})
+		_, tmpl, err := testInstance.Process(testMeta, internal.GenerateObj(nsYaml))
+		require.NoError(t, err)
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		assert.Contains(t, buf.String(), "  name: {{ .Values.namespaces.myOperatorSystem }}\n")
+	})
+	t.Run("same name as workload", func(t *testing.T) {
+		obj := internal.GenerateObj(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+  labels:
+    team: web`)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name", CreateNamespace: true})
+		testMeta.Load(obj)
+		testMeta.Load(internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: app`))
+		_, tmpl, err := testInstance.Process(testMeta, obj)
+		require.NoError(t, err)
+		assert.NotContains(t, tmpl.Values(), "app", "workload values must not be changed")
+		labels, _, _ := unstructured.NestedStringMap(tmpl.Values(), "namespaceMetadata", "app", "labels")
+		assert.Equal(t, map[string]string{"team": "web"}, labels)
+	})
+}
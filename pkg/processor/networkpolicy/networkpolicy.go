@@ -0,0 +1,238 @@
+package networkpolicy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/processor"
+	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	ipBlocksTempl = `%[1]s{{- range (index .Values.%[2]s.%[3]s %[4]d).ipBlocks }}
+%[1]s- ipBlock:
+%[1]s    {{- toYaml . | nindent %[5]d }}
+%[1]s{{- end }}`
+	portsTempl = `    ports:
+    {{- toYaml (index .Values.%[1]s.%[2]s %[3]d).ports | nindent 4 }}`
+	selectorLabelsTempl = `%[1]s{{- include "%[2]s.selectorLabels" . | nindent %[3]d }}`
+)
+
+var networkPolicyGVC = schema.GroupVersionKind{
+	Group:   "networking.k8s.io",
+	Version: "v1",
+	Kind:    "NetworkPolicy",
+}
+
+// New creates processor for k8s NetworkPolicy resource.
+func New() helmify.Processor {
+	return &networkPolicy{}
+}
+
+type networkPolicy struct{}
+
+// Process k8s NetworkPolicy object into template. Returns false if not capable of processing given resource type.
+func (r networkPolicy) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != networkPolicyGVC {
+		return false, nil, nil
+	}
+	np := networkingv1.NetworkPolicy{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &np)
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to cast to NetworkPolicy", err)
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	values := helmify.Values{}
+	// every policy is toggled separately, key is the same as of the enable toggle, see toggle.Wrap
+	toggleName := strcase.ToLowerCamel(name + "-" + obj.GetKind())
+	_, err = values.Add(true, toggleName, "enabled")
+	if err != nil {
+		return true, nil, err
+	}
+
+	spec := strings.Builder{}
+	spec.WriteString("spec:\n  podSelector:")
+	podSelector, err := processSelector(appMeta, &np.Spec.PodSelector, 4)
+	if err != nil {
+		return true, nil, err
+	}
+	spec.WriteString(podSelector)
+
+	if len(np.Spec.PolicyTypes) != 0 {
+		policyTypes, err := yamlformat.Marshal(map[string]interface{}{"policyTypes": np.Spec.PolicyTypes}, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		spec.WriteString("\n" + policyTypes)
+	}
+
+	if len(np.Spec.Ingress) != 0 {
+		rules := make([]rule, len(np.Spec.Ingress))
+		for i, in := range np.Spec.Ingress {
+			rules[i] = rule{peers: in.From, ports: in.Ports}
+		}
+		ingress, err := processRules(appMeta, nameCamel, "ingress", "from", rules, values)
+		if err != nil {
+			return true, nil, err
+		}
+		spec.WriteString("\n" + ingress)
+	}
+
+	if len(np.Spec.Egress) != 0 {
+		rules := make([]rule, len(np.Spec.Egress))
+		for i, eg := range np.Spec.Egress {
+			rules[i] = rule{peers: eg.To, ports: eg.Ports}
+		}
+		egress, err := processRules(appMeta, nameCamel, "egress", "to", rules, values)
+		if err != nil {
+			return true, nil, err
+		}
+		spec.WriteString("\n" + egress)
+	}
+
+	res := fmt.Sprintf("{{- if .Values.%s.enabled }}\n%s\n%s\n{{- end }}", toggleName, meta, spec.String())
+	return true, &result{
+		name:   name,
+		data:   res,
+		values: values,
+	}, nil
+}
+
+// rule - common representation of NetworkPolicy ingress and egress rules.
+type rule struct {
+	peers []networkingv1.NetworkPolicyPeer
+	ports []networkingv1.NetworkPolicyPort
+}
+
+// processRules templates ingress or egress rules. ipBlock peers and ports of every rule are moved to
+// values list under the same index as the rule.
+func processRules(appMeta helmify.AppMetadata, objName, direction, peersKey string, rules []rule, values helmify.Values) (string, error) {
+	res := strings.Builder{}
+	res.WriteString("  " + direction + ":")
+	valuesRules := make([]interface{}, len(rules))
+	for i, r := range rules {
+		valuesRule := map[string]interface{}{}
+		valuesRules[i] = valuesRule
+		if len(r.peers) == 0 && len(r.ports) == 0 {
+			res.WriteString("\n  - {}")
+			continue
+		}
+		prefix := "\n  - "
+		if len(r.peers) != 0 {
+			res.WriteString(prefix + peersKey + ":")
+			prefix = "\n    "
+			var ipBlocks []interface{}
+			for j := range r.peers {
+				peer := r.peers[j]
+				if peer.IPBlock != nil {
+					ipBlock, err := runtime.DefaultUnstructuredConverter.ToUnstructured(peer.IPBlock)
+					if err != nil {
+						return "", fmt.Errorf("%w: unable to convert ipBlock to unstructured", err)
+					}
+					ipBlocks = append(ipBlocks, ipBlock)
+					continue
+				}
+				peerStr, err := processPeer(appMeta, &peer)
+				if err != nil {
+					return "", err
+				}
+				res.WriteString("\n" + peerStr)
+			}
+			if len(ipBlocks) != 0 {
+				valuesRule["ipBlocks"] = ipBlocks
+				res.WriteString("\n" + fmt.Sprintf(ipBlocksTempl, "    ", objName, direction, i, 8))
+			}
+		}
+		if len(r.ports) != 0 {
+			ports := make([]interface{}, len(r.ports))
+			for j := range r.ports {
+				port, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&r.ports[j])
+				if err != nil {
+					return "", fmt.Errorf("%w: unable to convert port to unstructured", err)
+				}
+				ports[j] = port
+			}
+			valuesRule["ports"] = ports
+			res.WriteString(prefix + strings.TrimLeft(fmt.Sprintf(portsTempl, objName, direction, i), " "))
+		}
+	}
+	err := unstructured.SetNestedSlice(values, valuesRules, objName, direction)
+	if err != nil {
+		return "", fmt.Errorf("%w: unable to set %s rules value", err, direction)
+	}
+	return res.String(), nil
+}
+
+// processPeer templates single pod or namespace peer. Pod selectors of the peers from the same namespace
+// selecting app pods are extended with chart selector labels, see processSelector.
+func processPeer(appMeta helmify.AppMetadata, peer *networkingv1.NetworkPolicyPeer) (string, error) {
+	if peer.PodSelector == nil || peer.NamespaceSelector != nil {
+		return yamlformat.Marshal([]interface{}{peer}, 4)
+	}
+	podSelector, err := processSelector(appMeta, peer.PodSelector, 8)
+	if err != nil {
+		return "", err
+	}
+	return "    - podSelector:" + podSelector, nil
+}
+
+// processSelector returns label selector yaml. Chart selector labels are added to matchLabels selecting pods
+// of the chart workloads, selectors of other pods, e.g. ingress controller, are kept as is.
+// Result starts from space for inline empty selector and from new line otherwise.
+func processSelector(appMeta helmify.AppMetadata, selector *metav1.LabelSelector, indent int) (string, error) {
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		return " {}", nil
+	}
+	res := strings.Builder{}
+	if len(selector.MatchLabels) != 0 {
+		matchLabels, err := yamlformat.Marshal(map[string]interface{}{"matchLabels": selector.MatchLabels}, indent)
+		if err != nil {
+			return "", err
+		}
+		res.WriteString("\n" + matchLabels)
+		if appMeta.SelectsChartPods(selector.MatchLabels) {
+			res.WriteString("\n" + fmt.Sprintf(selectorLabelsTempl, strings.Repeat(" ", indent), appMeta.ChartName(), indent+2))
+		}
+	}
+	if len(selector.MatchExpressions) != 0 {
+		matchExpr, err := yamlformat.Marshal(map[string]interface{}{"matchExpressions": selector.MatchExpressions}, indent)
+		if err != nil {
+			return "", err
+		}
+		res.WriteString("\n" + matchExpr)
+	}
+	return res.String(), nil
+}
+
+type result struct {
+	name   string
+	data   string
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte(r.data))
+	return err
+}
@@ -0,0 +1,214 @@
+package networkpolicy
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const networkPolicyYaml = `apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: my-operator-allow-db
+  namespace: my-operator-system
+spec:
+  podSelector:
+    matchLabels:
+      app: db
+  policyTypes:
+  - Ingress
+  - Egress
+  ingress:
+  - from:
+    - ipBlock:
+        cidr: 172.17.0.0/16
+        except:
+        - 172.17.1.0/24
+    - namespaceSelector:
+        matchLabels:
+          project: myproject
+    - podSelector:
+        matchLabels:
+          role: frontend
+    ports:
+    - protocol: TCP
+      port: 6379
+  egress:
+  - to:
+    - ipBlock:
+        cidr: 10.0.0.0/24
+    ports:
+    - protocol: TCP
+      port: 5978`
+
+const networkPolicyTempl = `{{- if .Values.allowDbNetworkPolicy.enabled }}
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: {{ include "chart-name.fullname" . }}-allow-db
+  labels:
+  {{- include "chart-name.labels" . | nindent 4 }}
+spec:
+  podSelector:
+    matchLabels:
+      app: db
+    {{- include "chart-name.selectorLabels" . | nindent 6 }}
+  policyTypes:
+  - Ingress
+  - Egress
+  ingress:
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          project: myproject
+    - podSelector:
+        matchLabels:
+          role: frontend
+        {{- include "chart-name.selectorLabels" . | nindent 10 }}
+    {{- range (index .Values.allowDb.ingress 0).ipBlocks }}
+    - ipBlock:
+        {{- toYaml . | nindent 8 }}
+    {{- end }}
+    ports:
+    {{- toYaml (index .Values.allowDb.ingress 0).ports | nindent 4 }}
+  egress:
+  - to:
+    {{- range (index .Values.allowDb.egress 0).ipBlocks }}
+    - ipBlock:
+        {{- toYaml . | nindent 8 }}
+    {{- end }}
+    ports:
+    {{- toYaml (index .Values.allowDb.egress 0).ports | nindent 4 }}
+{{- end }}`
+
+func Test_networkPolicy_Process(t *testing.T) {
+	var testInstance networkPolicy
+
+	t.Run("processed", func(t *testing.T) {
+		obj := internal.GenerateObj(networkPolicyYaml)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(obj)
+		testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-db
+  namespace: my-operator-system`))
+		testMeta.Load(deployment("my-operator-db", "app: db"))
+		testMeta.Load(deployment("my-operator-frontend", "role: frontend"))
+		processed, tt, err := testInstance.Process(testMeta, obj)
+		require.NoError(t, err)
+		assert.Equal(t, true, processed)
+		buf := bytes.Buffer{}
+		require.NoError(t, tt.Write(&buf))
+		assert.Equal(t, networkPolicyTempl, buf.String())
+		assert.Equal(t, helmify.Values{
+			"allowDbNetworkPolicy": map[string]interface{}{"enabled": true},
+			"allowDb": map[string]interface{}{
+				"ingress": []interface{}{
+					map[string]interface{}{
+						"ipBlocks": []interface{}{
+							map[string]interface{}{
+								"cidr":   "172.17.0.0/16",
+								"except": []interface{}{"172.17.1.0/24"},
+							},
+						},
+						"ports": []interface{}{
+							map[string]interface{}{"protocol": "TCP", "port": int64(6379)},
+						},
+					},
+				},
+				"egress": []interface{}{
+					map[string]interface{}{
+						"ipBlocks": []interface{}{
+							map[string]interface{}{"cidr": "10.0.0.0/24"},
+						},
+						"ports": []interface{}{
+							map[string]interface{}{"protocol": "TCP", "port": int64(5978)},
+						},
+					},
+				},
+			},
+		}, tt.Values())
+	})
+	t.Run("external pods", func(t *testing.T) {
+		obj := internal.GenerateObj(`apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: my-operator-allow-ingress
+spec:
+  podSelector:
+    matchLabels:
+      app: web
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          app.kubernetes.io/name: ingress-nginx
+    - podSelector:
+        matchLabels:
+          app: web`)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(obj)
+		testMeta.Load(deployment("my-operator-web", "app: web\n        tier: frontend"))
+		testMeta.Load(internal.GenerateObj(`apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-operator-ingress
+spec:
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: ingress-nginx`))
+		_, tt, err := testInstance.Process(testMeta, obj)
+		require.NoError(t, err)
+		enabled, _, _ := unstructured.NestedBool(tt.Values(), "allowIngressNetworkPolicy", "enabled")
+		assert.True(t, enabled, "every policy is toggled separately")
+		buf := bytes.Buffer{}
+		require.NoError(t, tt.Write(&buf))
+		assert.True(t, strings.HasPrefix(buf.String(), "{{- if .Values.allowIngressNetworkPolicy.enabled }}\n"))
+		assert.Contains(t, buf.String(), `spec:
+  podSelector:
+    matchLabels:
+      app: web
+    {{- include "chart-name.selectorLabels" . | nindent 6 }}
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          app.kubernetes.io/name: ingress-nginx
+    - podSelector:
+        matchLabels:
+          app: web
+        {{- include "chart-name.selectorLabels" . | nindent 10 }}
+{{- end }}`)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		obj := internal.TestNs
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func deployment(name, podLabels string) *unstructured.Unstructured {
+	return internal.GenerateObj(fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %s
+  namespace: my-operator-system
+spec:
+  template:
+    metadata:
+      labels:
+        %s`, name, podLabels))
+}
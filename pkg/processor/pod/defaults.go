@@ -0,0 +1,141 @@
+package pod
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Placeholders for values generated with config.Config.GenerateDefaults are added to unstructured pod templates
+// as marker keys and replaced with 'with' blocks by RenderDefaults after marshalling, when the indent is known.
+// Markers are in format:
+//
+//	defaultKeyPrefix<key>: <values path>   - renders key with the value if the value is not empty.
+//	defaultEntriesKey: <values path>       - renders entries of the map value into the enclosing map.
+//	- defaultEntriesKey: <values path>     - renders items of the list value into the enclosing list.
+//
+// Key markers may start a list item, e.g. the first key of a container without env, the dash is kept then.
+const (
+	defaultKeyPrefix  = "helmifyDefault."
+	defaultEntriesKey = "helmifyDefaultEntries"
+)
+
+var defaultRegexp = regexp.MustCompile(`(?m)^( *)(- )?(` + regexp.QuoteMeta(defaultKeyPrefix) + `(\w+)|` + defaultEntriesKey + `): (\S+)$`)
+
+// scalarDefaults - placeholders rendered inline.
+var scalarDefaults = map[string]bool{
+	"priorityClassName": true,
+}
+
+// processDefaults adds placeholders for pod scheduling options missing in the pod spec.
+func processDefaults(objName string, spec corev1.PodSpec, specMap map[string]interface{}, values helmify.Values) error {
+	placeholders := []struct {
+		key   string
+		value interface{}
+		unset bool
+	}{
+		{key: "nodeSelector", value: map[string]interface{}{}, unset: spec.NodeSelector == nil},
+		{key: "tolerations", value: []interface{}{}, unset: spec.Tolerations == nil},
+		{key: "affinity", value: map[string]interface{}{}, unset: spec.Affinity == nil},
+		{key: "topologySpreadConstraints", value: []interface{}{}, unset: spec.TopologySpreadConstraints == nil},
+		{key: "priorityClassName", value: "", unset: spec.PriorityClassName == ""},
+	}
+	for _, p := range placeholders {
+		if !p.unset {
+			continue
+		}
+		err := addDefault(values, p.value, objName, p.key)
+		if err != nil {
+			return err
+		}
+		specMap[defaultKeyPrefix+p.key] = objName + "." + p.key
+	}
+	return nil
+}
+
+// ProcessMetaDefaults adds podLabels and podAnnotations placeholders to the unstructured pod template metadata
+// if config.Config.GenerateDefaults is set. Rendered by RenderDefaults.
+func ProcessMetaDefaults(objName string, appMeta helmify.AppMetadata, metadata map[string]interface{}, values helmify.Values) error {
+	if !appMeta.Config().GenerateDefaults {
+		return nil
+	}
+	err := addDefault(values, map[string]interface{}{}, objName, "podLabels")
+	if err != nil {
+		return err
+	}
+	err = addDefault(values, map[string]interface{}{}, objName, "podAnnotations")
+	if err != nil {
+		return err
+	}
+	// without labels the key is rendered inside 'with' block, so empty podLabels render no null labels
+	if labels, ok := metadata["labels"].(map[string]interface{}); ok {
+		labels[defaultEntriesKey] = objName + ".podLabels"
+	} else {
+		metadata[defaultKeyPrefix+"labels"] = objName + ".podLabels"
+	}
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		annotations[defaultEntriesKey] = objName + ".podAnnotations"
+	} else {
+		metadata[defaultKeyPrefix+"annotations"] = objName + ".podAnnotations"
+	}
+	return nil
+}
+
+// TemplateMeta returns labels and annotations of the pod template as unstructured metadata
+// with placeholders added, see ProcessMetaDefaults.
+func TemplateMeta(objName string, appMeta helmify.AppMetadata, meta metav1.ObjectMeta, values helmify.Values) (map[string]interface{}, error) {
+	labels := map[string]interface{}{}
+	for k, v := range meta.Labels {
+		labels[k] = v
+	}
+	res := map[string]interface{}{"labels": labels}
+	if len(meta.Annotations) != 0 {
+		annotations := map[string]interface{}{}
+		for k, v := range meta.Annotations {
+			annotations[k] = v
+		}
+		res["annotations"] = annotations
+	}
+	return res, ProcessMetaDefaults(objName, appMeta, res, values)
+}
+
+func addDefault(values helmify.Values, value interface{}, name ...string) error {
+	_, err := values.Add(value, name...)
+	if err != nil {
+		return fmt.Errorf("%w: unable to set %s placeholder", err, strings.Join(name, "."))
+	}
+	return nil
+}
+
+// RenderDefaults replaces placeholder markers in the marshalled template with 'with' blocks,
+// so empty default values render nothing. Values are rendered with tpl as they may reference templated names of
+// chart objects, e.g. secretKeyRef of env variables moved into values with config.EnvList.
+func RenderDefaults(template string) string {
+	return defaultRegexp.ReplaceAllStringFunc(template, func(s string) string {
+		m := defaultRegexp.FindStringSubmatch(s)
+		indent, dash, key, path := m[1], m[2], m[4], m[5]
+		var lines []string
+		if dash != "" && key != "" {
+			// marker is the first key of a list item map
+			lines = append(lines, indent+"-")
+			indent += "  "
+		}
+		lines = append(lines, fmt.Sprintf("%s{{- with .Values.%s }}", indent, path))
+		switch {
+		case key == "":
+			lines = append(lines, fmt.Sprintf("%s{{- tpl (toYaml .) $ | nindent %d }}", indent, len(indent)))
+		case scalarDefaults[key]:
+			lines = append(lines, fmt.Sprintf("%s%s: {{ . }}", indent, key))
+		default:
+			lines = append(lines,
+				fmt.Sprintf("%s%s:", indent, key),
+				fmt.Sprintf("%s  {{- tpl (toYaml .) $ | nindent %d }}", indent, len(indent)+2))
+		}
+		lines = append(lines, indent+"{{- end }}")
+		return strings.Join(lines, "\n")
+	})
+}
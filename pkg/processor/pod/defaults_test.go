@@ -0,0 +1,125 @@
+package pod
+
+import (
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_processDefaults(t *testing.T) {
+	var deploy appsv1.Deployment
+	obj := internal.GenerateObj(strDeployment + `
+    spec:
+      nodeSelector:
+        disk: ssd
+      containers:
+      - name: nginx
+        image: nginx:1.14.2`)
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy))
+
+	t.Run("disabled", func(t *testing.T) {
+		specMap, values, err := ProcessSpec("nginx", metadata.New(config.Config{}), deploy.Spec.Template.Spec, nil)
+		require.NoError(t, err)
+		assert.NotContains(t, specMap, "helmifyDefault.tolerations")
+		assert.NotContains(t, values["nginx"], "tolerations")
+	})
+	t.Run("enabled", func(t *testing.T) {
+		specMap, values, err := ProcessSpec("nginx", metadata.New(config.Config{GenerateDefaults: true}), deploy.Spec.Template.Spec, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "nginx.tolerations", specMap["helmifyDefault.tolerations"])
+		assert.Equal(t, "nginx.priorityClassName", specMap["helmifyDefault.priorityClassName"])
+		assert.NotContains(t, specMap, "helmifyDefault.nodeSelector", "set in the source")
+		nginx := values["nginx"].(map[string]interface{})
+		assert.Equal(t, []interface{}{}, nginx["tolerations"])
+		assert.Equal(t, []interface{}{}, nginx["topologySpreadConstraints"])
+		assert.Equal(t, map[string]interface{}{}, nginx["affinity"])
+		assert.Equal(t, "", nginx["priorityClassName"])
+		assert.Equal(t, map[string]interface{}{"disk": "ssd"}, nginx["nodeSelector"])
+	})
+}
+
+func Test_TemplateMeta(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		values := helmify.Values{}
+		res, err := TemplateMeta("nginx", metadata.New(config.Config{}), deployMeta(nil), values)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"labels": map[string]interface{}{"app": "nginx"}}, res)
+		assert.Empty(t, values)
+	})
+	t.Run("without annotations", func(t *testing.T) {
+		values := helmify.Values{}
+		res, err := TemplateMeta("nginx", metadata.New(config.Config{GenerateDefaults: true}), deployMeta(nil), values)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"labels":                     map[string]interface{}{"app": "nginx", "helmifyDefaultEntries": "nginx.podLabels"},
+			"helmifyDefault.annotations": "nginx.podAnnotations",
+		}, res)
+		assert.Equal(t, helmify.Values{"nginx": map[string]interface{}{
+			"podLabels":      map[string]interface{}{},
+			"podAnnotations": map[string]interface{}{},
+		}}, values)
+	})
+	t.Run("with annotations", func(t *testing.T) {
+		res, err := TemplateMeta("nginx", metadata.New(config.Config{GenerateDefaults: true}),
+			deployMeta(map[string]string{"a": "b"}), helmify.Values{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"a": "b", "helmifyDefaultEntries": "nginx.podAnnotations"}, res["annotations"])
+	})
+}
+
+func Test_RenderDefaults(t *testing.T) {
+	const tmpl = `    metadata:
+      labels:
+        app: nginx
+        helmifyDefaultEntries: nginx.podLabels
+      helmifyDefault.annotations: nginx.podAnnotations
+    spec:
+      helmifyDefault.priorityClassName: nginx.priorityClassName
+      helmifyDefault.tolerations: nginx.tolerations`
+	assert.Equal(t, `    metadata:
+      labels:
+        app: nginx
+        {{- with .Values.nginx.podLabels }}
+        {{- tpl (toYaml .) $ | nindent 8 }}
+        {{- end }}
+      {{- with .Values.nginx.podAnnotations }}
+      annotations:
+        {{- tpl (toYaml .) $ | nindent 8 }}
+      {{- end }}
+    spec:
+      {{- with .Values.nginx.priorityClassName }}
+      priorityClassName: {{ . }}
+      {{- end }}
+      {{- with .Values.nginx.tolerations }}
+      tolerations:
+        {{- tpl (toYaml .) $ | nindent 8 }}
+      {{- end }}`, RenderDefaults(tmpl))
+
+	assert.Equal(t, `      containers:
+      -
+        {{- with .Values.web.web.extraEnv }}
+        env:
+          {{- tpl (toYaml .) $ | nindent 10 }}
+        {{- end }}
+        image: nginx
+        volumeMounts:
+        {{- with .Values.web.web.extraVolumeMounts }}
+        {{- tpl (toYaml .) $ | nindent 8 }}
+        {{- end }}`, RenderDefaults(`      containers:
+      - helmifyDefault.env: web.web.extraEnv
+        image: nginx
+        volumeMounts:
+        - helmifyDefaultEntries: web.web.extraVolumeMounts`), "dash of the list item must be kept")
+}
+
+func deployMeta(annotations map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Labels: map[string]string{"app": "nginx"}, Annotations: annotations}
+}
@@ -0,0 +1,137 @@
+package pod
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const envValue = "{{ quote .Values.%[1]s.%[2]s.%[3]s.%[4]s }}"
+
+// processEnv templates env variables of the container according to env mode, see config.EnvMode.
+// Containers with plain variables having the same values key in config.EnvMap mode fall back to config.EnvList mode.
+// Names of Secrets and ConfigMaps referenced by valueFrom are replaced with templated names in both modes.
+func processEnv(name string, appMeta helmify.AppMetadata, c corev1.Container, values *helmify.Values) (corev1.Container, error) {
+	c.Env = dedupeEnv(c.Name, c.Env)
+	for i := range c.Env {
+		from := c.Env[i].ValueFrom
+		switch {
+		case from == nil:
+		case from.SecretKeyRef != nil:
+			from.SecretKeyRef.Name = appMeta.TemplatedName(from.SecretKeyRef.Name)
+		case from.ConfigMapKeyRef != nil:
+			from.ConfigMapKeyRef.Name = appMeta.TemplatedName(from.ConfigMapKeyRef.Name)
+		case from.FieldRef != nil, from.ResourceFieldRef != nil:
+			// nothing to change here, keep the original value
+		}
+	}
+	if appMeta.Config().Env() == config.EnvList {
+		return processEnvList(name, c, values)
+	}
+	if first, second, collide := envKeyCollision(c.Env); collide {
+		logrus.WithFields(logrus.Fields{"Container": c.Name, "Env": first + ", " + second}).
+			Warnf("env variables have the same value name: env is moved into values in %q mode", config.EnvList)
+		return processEnvList(name, c, values)
+	}
+	return processEnvMap(name, c, values)
+}
+
+// envKey returns values key of the env variable in config.EnvMap mode.
+func envKey(name string) string {
+	return strcase.ToLowerCamel(strings.ToLower(name))
+}
+
+// envKeyCollision returns names of the first two plain env variables with the same values key.
+func envKeyCollision(env []corev1.EnvVar) (string, string, bool) {
+	keys := map[string]string{}
+	for _, e := range env {
+		if e.ValueFrom != nil {
+			continue
+		}
+		key := envKey(e.Name)
+		if other, ok := keys[key]; ok {
+			return other, e.Name, true
+		}
+		keys[key] = e.Name
+	}
+	return "", "", false
+}
+
+// processEnvMap moves plain values of env variables into values <name>.<container>.env.<nameCamel>.
+// Variables with valueFrom are kept in the template.
+func processEnvMap(name string, c corev1.Container, values *helmify.Values) (corev1.Container, error) {
+	containerName := strcase.ToLowerCamel(c.Name)
+	for i := range c.Env {
+		if c.Env[i].ValueFrom != nil {
+			continue
+		}
+		key := envKey(c.Env[i].Name)
+		err := unstructured.SetNestedField(*values, c.Env[i].Value, name, containerName, "env", key)
+		if err != nil {
+			return c, fmt.Errorf("%w: unable to set deployment value field", err)
+		}
+		c.Env[i].Value = fmt.Sprintf(envValue, name, containerName, "env", key)
+	}
+	return c, nil
+}
+
+// processEnvList moves the whole env list of the container into values <name>.<container>.env keeping the order
+// of variables. The list is rendered into the container env by the placeholder added in addEnvEntries.
+func processEnvList(name string, c corev1.Container, values *helmify.Values) (corev1.Container, error) {
+	if len(c.Env) == 0 {
+		return c, nil
+	}
+	env := make([]interface{}, len(c.Env))
+	for i := range c.Env {
+		unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&c.Env[i])
+		if err != nil {
+			return c, fmt.Errorf("%w: unable to convert env variable %s to unstructured", err, c.Env[i].Name)
+		}
+		env[i] = unstr
+	}
+	err := unstructured.SetNestedSlice(*values, env, name, strcase.ToLowerCamel(c.Name), "env")
+	if err != nil {
+		return c, fmt.Errorf("%w: unable to set container env value", err)
+	}
+	c.Env = nil
+	return c, nil
+}
+
+// addEnvEntries prepends placeholder of the env list moved into values by processEnvList to the container env.
+// Rendered by RenderDefaults.
+func addEnvEntries(objName, containerName string, container map[string]interface{}, values helmify.Values) {
+	if _, ok, _ := unstructured.NestedSlice(values, objName, containerName, "env"); !ok {
+		return
+	}
+	env, _ := container["env"].([]interface{})
+	entries := map[string]interface{}{defaultEntriesKey: strings.Join([]string{objName, containerName, "env"}, ".")}
+	container["env"] = append([]interface{}{entries}, env...)
+}
+
+// dedupeEnv removes duplicated env variables. Kubernetes uses the last definition of the variable,
+// so it is kept at the position of the first one.
+func dedupeEnv(containerName string, env []corev1.EnvVar) []corev1.EnvVar {
+	index := map[string]int{}
+	res := make([]corev1.EnvVar, 0, len(env))
+	for _, e := range env {
+		if i, ok := index[e.Name]; ok {
+			logrus.WithFields(logrus.Fields{"Container": containerName, "Env": e.Name}).
+				Warn("duplicated env variable: the last definition is used")
+			res[i] = e
+			continue
+		}
+		index[e.Name] = len(res)
+		res = append(res, e)
+	}
+	if len(env) == 0 {
+		return env
+	}
+	return res
+}
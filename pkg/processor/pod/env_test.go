@@ -0,0 +1,118 @@
+package pod
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const strEnv = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25.1
+        env:
+        - name: LOG_LEVEL
+          value: info
+        - name: DB_PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: my-app-db
+              key: password
+        - name: LOG_LEVEL
+          value: debug`
+
+func Test_processEnv(t *testing.T) {
+	load := func(t *testing.T, conf config.Config, env string) (*metadata.Service, appsv1.Deployment) {
+		var deploy appsv1.Deployment
+		obj := internal.GenerateObj(env)
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy))
+		conf.ChartName = "chart"
+		appMeta := metadata.New(conf)
+		appMeta.Load(obj)
+		appMeta.Load(internal.GenerateObj("apiVersion: v1\nkind: Secret\nmetadata:\n  name: my-app-db"))
+		return appMeta, deploy
+	}
+	dbPassword := map[string]interface{}{
+		"name": "DB_PASSWORD",
+		"valueFrom": map[string]interface{}{
+			"secretKeyRef": map[string]interface{}{"name": `{{ include "chart.fullname" . }}-db`, "key": "password"},
+		},
+	}
+
+	t.Run("map", func(t *testing.T) {
+		appMeta, deploy := load(t, config.Config{}, strEnv)
+		specMap, values, err := ProcessSpec("web", appMeta, deploy.Spec.Template.Spec, nil)
+		require.NoError(t, err)
+		env, _, _ := unstructured.NestedSlice(specMap, "containers")
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"name": "LOG_LEVEL", "value": "{{ quote .Values.web.web.env.logLevel }}"},
+			dbPassword,
+			map[string]interface{}{defaultEntriesKey: "web.web.extraEnv"},
+		}, env[0].(map[string]interface{})["env"], "duplicate must be removed keeping the position of the first one")
+		logLevel, _, _ := unstructured.NestedString(values, "web", "web", "env", "logLevel")
+		assert.Equal(t, "debug", logLevel, "the last definition must be used")
+	})
+	t.Run("map collision", func(t *testing.T) {
+		appMeta, deploy := load(t, config.Config{}, strEnv+`
+        - name: LOG-LEVEL
+          value: warn`)
+		_, values, err := ProcessSpec("web", appMeta, deploy.Spec.Template.Spec, nil)
+		require.NoError(t, err)
+		env, _, _ := unstructured.NestedSlice(values, "web", "web", "env")
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+			dbPassword,
+			map[string]interface{}{"name": "LOG-LEVEL", "value": "warn"},
+		}, env, "container must fall back to list mode")
+	})
+	t.Run("list", func(t *testing.T) {
+		appMeta, deploy := load(t, config.Config{EnvMode: config.EnvList, ClusterDomainEnv: true}, strEnv+`
+        - name: LOG-LEVEL
+          value: warn`)
+		specMap, values, err := ProcessSpec("web", appMeta, deploy.Spec.Template.Spec, nil)
+		require.NoError(t, err)
+		env, _, _ := unstructured.NestedSlice(values, "web", "web", "env")
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+			dbPassword,
+			map[string]interface{}{"name": "LOG-LEVEL", "value": "warn"},
+		}, env)
+
+		spec, err := yamlformat.Marshal(specMap, 6)
+		require.NoError(t, err)
+		spec = RenderDefaults(strings.ReplaceAll(spec, "'", ""))
+		assert.Contains(t, spec, `      - env:
+        {{- with .Values.web.web.env }}
+        {{- tpl (toYaml .) $ | nindent 8 }}
+        {{- end }}
+        - name: KUBERNETES_CLUSTER_DOMAIN
+          value: {{ quote .Values.kubernetesClusterDomain }}
+        {{- with .Values.web.web.extraEnv }}`)
+	})
+	t.Run("cluster domain env", func(t *testing.T) {
+		for _, enabled := range []bool{false, true} {
+			appMeta, deploy := load(t, config.Config{ClusterDomainEnv: enabled}, strEnv)
+			specMap, values, err := ProcessSpec("web", appMeta, deploy.Spec.Template.Spec, nil)
+			require.NoError(t, err)
+			spec, err := yamlformat.Marshal(specMap, 6)
+			require.NoError(t, err)
+			assert.Equal(t, enabled, strings.Contains(spec, "KUBERNETES_CLUSTER_DOMAIN"))
+			assert.Equal(t, enabled, values["kubernetesClusterDomain"] == "cluster.local")
+		}
+	})
+}
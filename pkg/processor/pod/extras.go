@@ -0,0 +1,65 @@
+package pod
+
+import (
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
+)
+
+// containerExtras - lists of the container extended with values <objName>.<container>.<extra key>.
+var containerExtras = []struct{ key, extra string }{
+	{key: "env", extra: "extraEnv"},
+	{key: "envFrom", extra: "extraEnvFrom"},
+	{key: "volumeMounts", extra: "extraVolumeMounts"},
+}
+
+// podExtras - lists of the pod spec extended with values <objName>.<extra key>.
+var podExtras = []struct{ key, extra string }{
+	{key: "volumes", extra: "extraVolumes"},
+	{key: "containers", extra: "extraContainers"},
+	{key: "initContainers", extra: "extraInitContainers"},
+}
+
+// processExtras adds empty extra lists to values and placeholders rendering them into the pod spec lists,
+// so users can add env variables, volumes and sidecars without editing templates. Rendered by RenderDefaults.
+func processExtras(objName string, specMap map[string]interface{}, values helmify.Values) error {
+	containers, _ := specMap["containers"].([]interface{})
+	initContainers, _ := specMap["initContainers"].([]interface{})
+	for _, c := range append(append([]interface{}{}, containers...), initContainers...) {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := container["name"].(string)
+		containerName := strcase.ToLowerCamel(name)
+		for _, e := range containerExtras {
+			err := addExtra(container, values, e.key, objName, containerName, e.extra)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range podExtras {
+		err := addExtra(specMap, values, e.key, objName, e.extra)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addExtra adds empty list value with the given name and appends its placeholder to the list under key.
+func addExtra(obj map[string]interface{}, values helmify.Values, key string, name ...string) error {
+	err := addDefault(values, []interface{}{}, name...)
+	if err != nil {
+		return err
+	}
+	path := strings.Join(name, ".")
+	if list, ok := obj[key].([]interface{}); ok {
+		obj[key] = append(list, map[string]interface{}{defaultEntriesKey: path})
+		return nil
+	}
+	obj[defaultKeyPrefix+key] = path
+	return nil
+}
@@ -0,0 +1,63 @@
+package pod
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_processExtras(t *testing.T) {
+	var deploy appsv1.Deployment
+	obj := internal.GenerateObj(strDeployment + `
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25.1
+        volumeMounts:
+        - name: data
+          mountPath: /data
+      volumes:
+      - name: data
+        emptyDir: {}`)
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy))
+	specMap, values, err := ProcessSpec("web", &metadata.Service{}, deploy.Spec.Template.Spec, nil)
+	require.NoError(t, err)
+
+	web := values["web"].(map[string]interface{})
+	for _, key := range []string{"extraVolumes", "extraContainers", "extraInitContainers"} {
+		assert.Equal(t, []interface{}{}, web[key], key)
+	}
+	for _, key := range []string{"extraEnv", "extraEnvFrom", "extraVolumeMounts"} {
+		assert.Equal(t, []interface{}{}, web["web"].(map[string]interface{})[key], key)
+	}
+
+	spec, err := yamlformat.Marshal(specMap, 6)
+	require.NoError(t, err)
+	spec = RenderDefaults(strings.ReplaceAll(spec, "'", ""))
+	assert.NotContains(t, spec, "helmifyDefault")
+	assert.Contains(t, spec, `        - {{- toYaml .Values.web.web.volumeMounts.data | nindent 10 }}
+        {{- with .Values.web.web.extraVolumeMounts }}
+        {{- tpl (toYaml .) $ | nindent 8 }}
+        {{- end }}`)
+	assert.Contains(t, spec, `        {{- with .Values.web.web.extraEnvFrom }}
+        envFrom:
+          {{- tpl (toYaml .) $ | nindent 10 }}
+        {{- end }}`)
+	assert.Contains(t, spec, `      {{- with .Values.web.extraContainers }}
+      {{- tpl (toYaml .) $ | nindent 6 }}
+      {{- end }}`)
+	assert.Contains(t, spec, `      {{- with .Values.web.extraInitContainers }}
+      initContainers:
+        {{- tpl (toYaml .) $ | nindent 8 }}
+      {{- end }}`)
+	assert.Contains(t, spec, `      {{- with .Values.web.extraVolumes }}
+      {{- tpl (toYaml .) $ | nindent 6 }}
+      {{- end }}`)
+}
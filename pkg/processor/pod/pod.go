@@ -14,9 +14,11 @@ import (
 )
 
 const imagePullPolicyTemplate = "{{ .Values.%[1]s.%[2]s.imagePullPolicy }}"
-const envValue = "{{ quote .Values.%[1]s.%[2]s.%[3]s.%[4]s }}"
 
-func ProcessSpec(objName string, appMeta helmify.AppMetadata, spec corev1.PodSpec) (map[string]interface{}, helmify.Values, error) {
+// ProcessSpec templates pod spec of the workload. podLabels are labels of the pod template used to recognize
+// label selectors of the workload own pods in affinity terms and topology spread constraints.
+// Pass podLabels only if the pod template is extended with chart selector labels, nil keeps selectors as is.
+func ProcessSpec(objName string, appMeta helmify.AppMetadata, spec corev1.PodSpec, podLabels map[string]string) (map[string]interface{}, helmify.Values, error) {
 	values, err := processPodSpec(objName, appMeta, &spec)
 	if err != nil {
 		return nil, nil, err
@@ -66,17 +68,17 @@ func ProcessSpec(objName string, appMeta helmify.AppMetadata, spec corev1.PodSpe
 				return nil, nil, fmt.Errorf("%w: unable to set volume value", err)
 			}
 
-			// Replace volume with template
-			specMap["volumes"].([]interface{})[i] = fmt.Sprintf(`{{- toYaml .Values.%s.volumes.%s | nindent 8 }}`, objName, volNameCamel)
+			// Replace volume with template. Rendered with tpl because volumes reference templated names of ConfigMaps and Secrets.
+			specMap["volumes"].([]interface{})[i] = fmt.Sprintf(`{{- tpl (toYaml .Values.%s.volumes.%s) $ | nindent 8 }}`, objName, volNameCamel)
 		}
 	}
 
-	specMap, values, err = processNestedContainers(specMap, objName, values, "containers")
+	specMap, values, err = processNestedContainers(specMap, objName, values, "containers", appMeta.Config().AddWebhookOption)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	specMap, values, err = processNestedContainers(specMap, objName, values, "initContainers")
+	specMap, values, err = processNestedContainers(specMap, objName, values, "initContainers", appMeta.Config().AddWebhookOption)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -144,17 +146,34 @@ func ProcessSpec(objName string, appMeta helmify.AppMetadata, spec corev1.PodSpe
 		}
 	}
 
+	err = processScheduling(objName, appMeta, spec, podLabels, specMap, values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if appMeta.Config().GenerateDefaults {
+		err = processDefaults(objName, spec, specMap, values)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = processExtras(objName, specMap, values)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	return specMap, values, nil
 }
 
-func processNestedContainers(specMap map[string]interface{}, objName string, values map[string]interface{}, containerKey string) (map[string]interface{}, map[string]interface{}, error) {
+func processNestedContainers(specMap map[string]interface{}, objName string, values map[string]interface{}, containerKey string, webhookOption bool) (map[string]interface{}, map[string]interface{}, error) {
 	containers, _, err := unstructured.NestedSlice(specMap, containerKey)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	if len(containers) > 0 {
-		containers, values, err = processContainers(objName, values, containerKey, containers)
+		containers, values, err = processContainers(objName, values, containerKey, containers, webhookOption)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -168,7 +187,7 @@ func processNestedContainers(specMap map[string]interface{}, objName string, val
 	return specMap, values, nil
 }
 
-func processContainers(objName string, values helmify.Values, containerType string, containers []interface{}) ([]interface{}, helmify.Values, error) {
+func processContainers(objName string, values helmify.Values, containerType string, containers []interface{}, webhookOption bool) ([]interface{}, helmify.Values, error) {
 	for i := range containers {
 		containerName := strcase.ToLowerCamel((containers[i].(map[string]interface{})["name"]).(string))
 
@@ -216,10 +235,67 @@ func processContainers(objName string, values helmify.Values, containerType stri
 				return nil, nil, fmt.Errorf("%w: unable to set deployment value field", err)
 			}
 		}
+
+		addEnvEntries(objName, containerName, containers[i].(map[string]interface{}), values)
+
+		err = processContainerFields(objName, containerName, containers[i].(map[string]interface{}), values, webhookOption)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 	return containers, values, nil
 }
 
+// containerFields - container fields moved into values with indentation of the rendered value.
+var containerFields = []struct {
+	key    string
+	indent int
+}{
+	{key: "command", indent: 8},
+	{key: "ports", indent: 8},
+	{key: "livenessProbe", indent: 10},
+	{key: "readinessProbe", indent: 10},
+	{key: "startupProbe", indent: 10},
+	{key: "lifecycle", indent: 10},
+}
+
+// processContainerFields moves command, ports, probes, lifecycle hooks and workingDir of the container into values.
+// Ports of the webhook server are kept as is if webhookOption is set, see config.Config.AddWebhookOption.
+func processContainerFields(objName, containerName string, container map[string]interface{}, values helmify.Values, webhookOption bool) error {
+	for _, f := range containerFields {
+		value, ok := container[f.key]
+		if !ok {
+			continue
+		}
+		if f.key == "ports" && webhookOption && hasWebhookPort(value) {
+			continue
+		}
+		err := unstructured.SetNestedField(values, value, objName, containerName, f.key)
+		if err != nil {
+			return fmt.Errorf("%w: unable to set container %s value", err, f.key)
+		}
+		container[f.key] = fmt.Sprintf(`{{- toYaml .Values.%s.%s.%s | nindent %d }}`, objName, containerName, f.key, f.indent)
+	}
+	if workingDir, ok := container["workingDir"].(string); ok && workingDir != "" {
+		tpl, err := values.Add(workingDir, objName, containerName, "workingDir")
+		if err != nil {
+			return fmt.Errorf("%w: unable to set container workingDir value", err)
+		}
+		container["workingDir"] = tpl
+	}
+	return nil
+}
+
+func hasWebhookPort(ports interface{}) bool {
+	list, _ := ports.([]interface{})
+	for _, p := range list {
+		if port, ok := p.(map[string]interface{}); ok && port["name"] == "webhook-server" {
+			return true
+		}
+	}
+	return false
+}
+
 func processPodSpec(name string, appMeta helmify.AppMetadata, pod *corev1.PodSpec) (helmify.Values, error) {
 	values := helmify.Values{}
 	for i, c := range pod.Containers {
@@ -290,10 +366,13 @@ func processPodContainer(name string, appMeta helmify.AppMetadata, c corev1.Cont
 			e.ConfigMapRef.Name = appMeta.TemplatedName(e.ConfigMapRef.Name)
 		}
 	}
-	c.Env = append(c.Env, corev1.EnvVar{
-		Name:  cluster.DomainEnv,
-		Value: fmt.Sprintf("{{ quote .Values.%s }}", cluster.DomainKey),
-	})
+	if appMeta.Config().ClusterDomainEnv {
+		(*values)[cluster.DomainKey] = cluster.DefaultDomain
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  cluster.DomainEnv,
+			Value: fmt.Sprintf("{{ quote .Values.%s }}", cluster.DomainKey),
+		})
+	}
 	for k, v := range c.Resources.Requests {
 		err = unstructured.SetNestedField(*values, v.ToUnstructured(), name, containerName, "resources", "requests", k.String())
 		if err != nil {
@@ -317,26 +396,20 @@ func processPodContainer(name string, appMeta helmify.AppMetadata, c corev1.Cont
 	return c, nil
 }
 
-func processEnv(name string, appMeta helmify.AppMetadata, c corev1.Container, values *helmify.Values) (corev1.Container, error) {
-	containerName := strcase.ToLowerCamel(c.Name)
-	for i := 0; i < len(c.Env); i++ {
-		if c.Env[i].ValueFrom != nil {
-			switch {
-			case c.Env[i].ValueFrom.SecretKeyRef != nil:
-				c.Env[i].ValueFrom.SecretKeyRef.Name = appMeta.TemplatedName(c.Env[i].ValueFrom.SecretKeyRef.Name)
-			case c.Env[i].ValueFrom.ConfigMapKeyRef != nil:
-				c.Env[i].ValueFrom.ConfigMapKeyRef.Name = appMeta.TemplatedName(c.Env[i].ValueFrom.ConfigMapKeyRef.Name)
-			case c.Env[i].ValueFrom.FieldRef != nil, c.Env[i].ValueFrom.ResourceFieldRef != nil:
-				// nothing to change here, keep the original value
+// Schema returns JSON schema keywords for pod values produced by ProcessSpec.
+func Schema(objName string, spec corev1.PodSpec) helmify.Schema {
+	schema := helmify.Schema{}
+	if spec.DNSPolicy != "" {
+		dnsPolicies := []string{string(corev1.DNSClusterFirstWithHostNet), string(corev1.DNSClusterFirst), string(corev1.DNSDefault), string(corev1.DNSNone)}
+		schema.Add("enum", dnsPolicies, objName, "dnsPolicy")
+	}
+	pullPolicies := []string{string(corev1.PullAlways), string(corev1.PullIfNotPresent), string(corev1.PullNever)}
+	for _, containers := range [][]corev1.Container{spec.Containers, spec.InitContainers} {
+		for _, c := range containers {
+			if c.ImagePullPolicy != "" {
+				schema.Add("enum", pullPolicies, objName, c.Name, "imagePullPolicy")
 			}
-			continue
 		}
-
-		err := unstructured.SetNestedField(*values, c.Env[i].Value, name, containerName, "env", strcase.ToLowerCamel(strings.ToLower(c.Env[i].Name)))
-		if err != nil {
-			return c, fmt.Errorf("%w: unable to set deployment value field", err)
-		}
-		c.Env[i].Value = fmt.Sprintf(envValue, name, containerName, "env", strcase.ToLowerCamel(strings.ToLower(c.Env[i].Name)))
 	}
-	return c, nil
+	return schema
 }
@@ -3,13 +3,16 @@ package pod
 import (
 	"testing"
 
+	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 	"github.com/EdgeGamingGG/helmify/pkg/metadata"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/EdgeGamingGG/helmify/internal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -201,33 +204,35 @@ func Test_pod_Process(t *testing.T) {
 		var deploy appsv1.Deployment
 		obj := internal.GenerateObj(strDeployment)
 		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy)
-		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec)
+		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec, nil)
 		assert.NoError(t, err)
 
 		assert.Equal(t, map[string]interface{}{
 			"containers": []interface{}{
 				map[string]interface{}{
-					"args": "{{- toYaml .Values.nginx.nginx.args | nindent 8 }}",
-					"env": []interface{}{
-						map[string]interface{}{
-							"name":  "KUBERNETES_CLUSTER_DOMAIN",
-							"value": "{{ quote .Values.kubernetesClusterDomain }}",
-						},
-					},
-					"image": "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
-					"name":  "nginx", "ports": []interface{}{
-						map[string]interface{}{
-							"containerPort": int64(80),
-						},
-					},
-					"resources": map[string]interface{}{},
+					"args":                        "{{- toYaml .Values.nginx.nginx.args | nindent 8 }}",
+					"helmifyDefault.env":          "nginx.nginx.extraEnv",
+					"image":                       "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
+					"name":                        "nginx",
+					"ports":                       "{{- toYaml .Values.nginx.nginx.ports | nindent 8 }}",
+					"resources":                   map[string]interface{}{},
+					"helmifyDefault.envFrom":      "nginx.nginx.extraEnvFrom",
+					"helmifyDefault.volumeMounts": "nginx.nginx.extraVolumeMounts",
 				},
+				map[string]interface{}{"helmifyDefaultEntries": "nginx.extraContainers"},
 			},
+			"helmifyDefault.volumes":        "nginx.extraVolumes",
+			"helmifyDefault.initContainers": "nginx.extraInitContainers",
 		}, specMap)
 
 		assert.Equal(t, helmify.Values{
 			"nginx": map[string]interface{}{
 				"nginx": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{
+							"containerPort": int64(80),
+						},
+					},
 					"image": map[string]interface{}{
 						"repository": "nginx",
 						"tag":        "1.14.2",
@@ -236,7 +241,13 @@ func Test_pod_Process(t *testing.T) {
 						"--test",
 						"--arg",
 					},
+					"extraEnv":          []interface{}{},
+					"extraEnvFrom":      []interface{}{},
+					"extraVolumeMounts": []interface{}{},
 				},
+				"extraVolumes":        []interface{}{},
+				"extraContainers":     []interface{}{},
+				"extraInitContainers": []interface{}{},
 			},
 		}, tmpl)
 	})
@@ -245,37 +256,45 @@ func Test_pod_Process(t *testing.T) {
 		var deploy appsv1.Deployment
 		obj := internal.GenerateObj(strDeploymentWithNoArgs)
 		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy)
-		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec)
+		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec, nil)
 		assert.NoError(t, err)
 
 		assert.Equal(t, map[string]interface{}{
 			"containers": []interface{}{
 				map[string]interface{}{
-					"env": []interface{}{
-						map[string]interface{}{
-							"name":  "KUBERNETES_CLUSTER_DOMAIN",
-							"value": "{{ quote .Values.kubernetesClusterDomain }}",
-						},
-					},
-					"image": "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
-					"name":  "nginx", "ports": []interface{}{
-						map[string]interface{}{
-							"containerPort": int64(80),
-						},
-					},
-					"resources": map[string]interface{}{},
+					"helmifyDefault.env":          "nginx.nginx.extraEnv",
+					"image":                       "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
+					"name":                        "nginx",
+					"ports":                       "{{- toYaml .Values.nginx.nginx.ports | nindent 8 }}",
+					"resources":                   map[string]interface{}{},
+					"helmifyDefault.envFrom":      "nginx.nginx.extraEnvFrom",
+					"helmifyDefault.volumeMounts": "nginx.nginx.extraVolumeMounts",
 				},
+				map[string]interface{}{"helmifyDefaultEntries": "nginx.extraContainers"},
 			},
+			"helmifyDefault.volumes":        "nginx.extraVolumes",
+			"helmifyDefault.initContainers": "nginx.extraInitContainers",
 		}, specMap)
 
 		assert.Equal(t, helmify.Values{
 			"nginx": map[string]interface{}{
 				"nginx": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{
+							"containerPort": int64(80),
+						},
+					},
 					"image": map[string]interface{}{
 						"repository": "nginx",
 						"tag":        "1.14.2",
 					},
+					"extraEnv":          []interface{}{},
+					"extraEnvFrom":      []interface{}{},
+					"extraVolumeMounts": []interface{}{},
 				},
+				"extraVolumes":        []interface{}{},
+				"extraContainers":     []interface{}{},
+				"extraInitContainers": []interface{}{},
 			},
 		}, tmpl)
 	})
@@ -284,37 +303,45 @@ func Test_pod_Process(t *testing.T) {
 		var deploy appsv1.Deployment
 		obj := internal.GenerateObj(strDeploymentWithTagAndDigest)
 		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy)
-		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec)
+		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec, nil)
 		assert.NoError(t, err)
 
 		assert.Equal(t, map[string]interface{}{
 			"containers": []interface{}{
 				map[string]interface{}{
-					"env": []interface{}{
-						map[string]interface{}{
-							"name":  "KUBERNETES_CLUSTER_DOMAIN",
-							"value": "{{ quote .Values.kubernetesClusterDomain }}",
-						},
-					},
-					"image": "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
-					"name":  "nginx", "ports": []interface{}{
-						map[string]interface{}{
-							"containerPort": int64(80),
-						},
-					},
-					"resources": map[string]interface{}{},
+					"helmifyDefault.env":          "nginx.nginx.extraEnv",
+					"image":                       "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
+					"name":                        "nginx",
+					"ports":                       "{{- toYaml .Values.nginx.nginx.ports | nindent 8 }}",
+					"resources":                   map[string]interface{}{},
+					"helmifyDefault.envFrom":      "nginx.nginx.extraEnvFrom",
+					"helmifyDefault.volumeMounts": "nginx.nginx.extraVolumeMounts",
 				},
+				map[string]interface{}{"helmifyDefaultEntries": "nginx.extraContainers"},
 			},
+			"helmifyDefault.volumes":        "nginx.extraVolumes",
+			"helmifyDefault.initContainers": "nginx.extraInitContainers",
 		}, specMap)
 
 		assert.Equal(t, helmify.Values{
 			"nginx": map[string]interface{}{
 				"nginx": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{
+							"containerPort": int64(80),
+						},
+					},
 					"image": map[string]interface{}{
 						"repository": "nginx",
 						"tag":        "1.14.2@sha256:cb5c1bddd1b5665e1867a7fa1b5fa843a47ee433bbb75d4293888b71def53229",
 					},
+					"extraEnv":          []interface{}{},
+					"extraEnvFrom":      []interface{}{},
+					"extraVolumeMounts": []interface{}{},
 				},
+				"extraVolumes":        []interface{}{},
+				"extraContainers":     []interface{}{},
+				"extraInitContainers": []interface{}{},
 			},
 		}, tmpl)
 	})
@@ -323,37 +350,45 @@ func Test_pod_Process(t *testing.T) {
 		var deploy appsv1.Deployment
 		obj := internal.GenerateObj(strDeploymentWithPort)
 		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy)
-		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec)
+		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec, nil)
 		assert.NoError(t, err)
 
 		assert.Equal(t, map[string]interface{}{
 			"containers": []interface{}{
 				map[string]interface{}{
-					"env": []interface{}{
-						map[string]interface{}{
-							"name":  "KUBERNETES_CLUSTER_DOMAIN",
-							"value": "{{ quote .Values.kubernetesClusterDomain }}",
-						},
-					},
-					"image": "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
-					"name":  "nginx", "ports": []interface{}{
-						map[string]interface{}{
-							"containerPort": int64(80),
-						},
-					},
-					"resources": map[string]interface{}{},
+					"helmifyDefault.env":          "nginx.nginx.extraEnv",
+					"image":                       "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
+					"name":                        "nginx",
+					"ports":                       "{{- toYaml .Values.nginx.nginx.ports | nindent 8 }}",
+					"resources":                   map[string]interface{}{},
+					"helmifyDefault.envFrom":      "nginx.nginx.extraEnvFrom",
+					"helmifyDefault.volumeMounts": "nginx.nginx.extraVolumeMounts",
 				},
+				map[string]interface{}{"helmifyDefaultEntries": "nginx.extraContainers"},
 			},
+			"helmifyDefault.volumes":        "nginx.extraVolumes",
+			"helmifyDefault.initContainers": "nginx.extraInitContainers",
 		}, specMap)
 
 		assert.Equal(t, helmify.Values{
 			"nginx": map[string]interface{}{
 				"nginx": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{
+							"containerPort": int64(80),
+						},
+					},
 					"image": map[string]interface{}{
 						"repository": "localhost:6001/my_project",
 						"tag":        "latest",
 					},
+					"extraEnv":          []interface{}{},
+					"extraEnvFrom":      []interface{}{},
+					"extraVolumeMounts": []interface{}{},
 				},
+				"extraVolumes":        []interface{}{},
+				"extraContainers":     []interface{}{},
+				"extraInitContainers": []interface{}{},
 			},
 		}, tmpl)
 	})
@@ -361,23 +396,23 @@ func Test_pod_Process(t *testing.T) {
 		var deploy appsv1.Deployment
 		obj := internal.GenerateObj(strDeploymentWithPodSecurityContext)
 		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy)
-		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec)
+		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, map[string]interface{}{
 			"containers": []interface{}{
 				map[string]interface{}{
-					"env": []interface{}{
-						map[string]interface{}{
-							"name":  "KUBERNETES_CLUSTER_DOMAIN",
-							"value": "{{ quote .Values.kubernetesClusterDomain }}",
-						},
-					},
-					"image":     "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
-					"name":      "nginx",
-					"resources": map[string]interface{}{},
+					"helmifyDefault.env":          "nginx.nginx.extraEnv",
+					"image":                       "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
+					"name":                        "nginx",
+					"resources":                   map[string]interface{}{},
+					"helmifyDefault.envFrom":      "nginx.nginx.extraEnvFrom",
+					"helmifyDefault.volumeMounts": "nginx.nginx.extraVolumeMounts",
 				},
+				map[string]interface{}{"helmifyDefaultEntries": "nginx.extraContainers"},
 			},
-			"securityContext": "{{- toYaml .Values.nginx.podSecurityContext | nindent 8 }}",
+			"securityContext":               "{{- toYaml .Values.nginx.podSecurityContext | nindent 8 }}",
+			"helmifyDefault.volumes":        "nginx.extraVolumes",
+			"helmifyDefault.initContainers": "nginx.extraInitContainers",
 		}, specMap)
 
 		assert.Equal(t, helmify.Values{
@@ -393,7 +428,13 @@ func Test_pod_Process(t *testing.T) {
 						"repository": "localhost:6001/my_project",
 						"tag":        "latest",
 					},
+					"extraEnv":          []interface{}{},
+					"extraEnvFrom":      []interface{}{},
+					"extraVolumeMounts": []interface{}{},
 				},
+				"extraVolumes":        []interface{}{},
+				"extraContainers":     []interface{}{},
+				"extraInitContainers": []interface{}{},
 			},
 		}, tmpl)
 	})
@@ -402,38 +443,42 @@ func Test_pod_Process(t *testing.T) {
 		var deploy appsv1.Deployment
 		obj := internal.GenerateObj(strDeploymentWithTolerations)
 		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy)
-		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec)
+		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, deploy.Spec.Template.Spec, nil)
 		assert.NoError(t, err)
 
 		assert.Equal(t, map[string]interface{}{
 			"containers": []interface{}{
 				map[string]interface{}{
-					"env": []interface{}{
-						map[string]interface{}{
-							"name":  "KUBERNETES_CLUSTER_DOMAIN",
-							"value": "{{ quote .Values.kubernetesClusterDomain }}",
-						},
-					},
-					"image": "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
-					"name":  "nginx",
-					"ports": []interface{}{
-						map[string]interface{}{
-							"containerPort": int64(80),
-						},
-					},
-					"resources": map[string]interface{}{},
+					"helmifyDefault.env":          "nginx.nginx.extraEnv",
+					"image":                       "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
+					"name":                        "nginx",
+					"ports":                       "{{- toYaml .Values.nginx.nginx.ports | nindent 8 }}",
+					"resources":                   map[string]interface{}{},
+					"helmifyDefault.envFrom":      "nginx.nginx.extraEnvFrom",
+					"helmifyDefault.volumeMounts": "nginx.nginx.extraVolumeMounts",
 				},
+				map[string]interface{}{"helmifyDefaultEntries": "nginx.extraContainers"},
 			},
-			"tolerations": "{{- toYaml .Values.nginx.tolerations | nindent 8 }}",
+			"tolerations":                   "{{- toYaml .Values.nginx.tolerations | nindent 8 }}",
+			"helmifyDefault.volumes":        "nginx.extraVolumes",
+			"helmifyDefault.initContainers": "nginx.extraInitContainers",
 		}, specMap)
 
 		assert.Equal(t, helmify.Values{
 			"nginx": map[string]interface{}{
 				"nginx": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{
+							"containerPort": int64(80),
+						},
+					},
 					"image": map[string]interface{}{
 						"repository": "nginx",
 						"tag":        "1.14.2",
 					},
+					"extraEnv":          []interface{}{},
+					"extraEnvFrom":      []interface{}{},
+					"extraVolumeMounts": []interface{}{},
 				},
 				"tolerations": []interface{}{
 					map[string]interface{}{
@@ -448,6 +493,9 @@ func Test_pod_Process(t *testing.T) {
 						"effect":   "NoExecute",
 					},
 				},
+				"extraVolumes":        []interface{}{},
+				"extraContainers":     []interface{}{},
+				"extraInitContainers": []interface{}{},
 			},
 		}, tmpl)
 	})
@@ -456,45 +504,110 @@ func Test_pod_Process(t *testing.T) {
 		var dae appsv1.DaemonSet
 		obj := internal.GenerateObj(strDeploymentWithNodeSelector)
 		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &dae)
-		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, dae.Spec.Template.Spec)
+		specMap, tmpl, err := ProcessSpec("nginx", &metadata.Service{}, dae.Spec.Template.Spec, nil)
 		assert.NoError(t, err)
 
 		assert.Equal(t, map[string]interface{}{
 			"containers": []interface{}{
 				map[string]interface{}{
-					"env": []interface{}{
-						map[string]interface{}{
-							"name":  "KUBERNETES_CLUSTER_DOMAIN",
-							"value": "{{ quote .Values.kubernetesClusterDomain }}",
-						},
-					},
-					"image": "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
-					"name":  "nginx",
-					"ports": []interface{}{
-						map[string]interface{}{
-							"containerPort": int64(80),
-						},
-					},
-					"resources": map[string]interface{}{},
+					"helmifyDefault.env":          "nginx.nginx.extraEnv",
+					"image":                       "{{ .Values.nginx.nginx.image.repository }}:{{ .Values.nginx.nginx.image.tag | default .Chart.AppVersion }}",
+					"name":                        "nginx",
+					"ports":                       "{{- toYaml .Values.nginx.nginx.ports | nindent 8 }}",
+					"resources":                   map[string]interface{}{},
+					"helmifyDefault.envFrom":      "nginx.nginx.extraEnvFrom",
+					"helmifyDefault.volumeMounts": "nginx.nginx.extraVolumeMounts",
 				},
+				map[string]interface{}{"helmifyDefaultEntries": "nginx.extraContainers"},
 			},
-			"nodeSelector": "{{- toYaml .Values.nginx.nodeSelector | nindent 8 }}",
+			"nodeSelector":                  "{{- toYaml .Values.nginx.nodeSelector | nindent 8 }}",
+			"helmifyDefault.volumes":        "nginx.extraVolumes",
+			"helmifyDefault.initContainers": "nginx.extraInitContainers",
 		}, specMap)
 
 		assert.Equal(t, helmify.Values{
 			"nginx": map[string]interface{}{
 				"nginx": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{
+							"containerPort": int64(80),
+						},
+					},
 					"image": map[string]interface{}{
 						"repository": "nginx",
 						"tag":        "1.14.2",
 					},
+					"extraEnv":          []interface{}{},
+					"extraEnvFrom":      []interface{}{},
+					"extraVolumeMounts": []interface{}{},
 				},
 				"nodeSelector": map[string]interface{}{
 					"disktype": "ssd",
 					"region":   "us-east-1",
 				},
+				"extraVolumes":        []interface{}{},
+				"extraContainers":     []interface{}{},
+				"extraInitContainers": []interface{}{},
 			},
 		}, tmpl)
 	})
 
 }
+
+func Test_processContainerFields(t *testing.T) {
+	const strContainer = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25.1
+        command: [nginx]
+        workingDir: /srv
+        ports:
+        - containerPort: 9443
+          name: webhook-server
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8081
+          periodSeconds: 20
+        lifecycle:
+          preStop:
+            exec:
+              command: [sleep, "5"]`
+	var deploy appsv1.Deployment
+	obj := internal.GenerateObj(strContainer)
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy))
+
+	t.Run("templated", func(t *testing.T) {
+		specMap, values, err := ProcessSpec("web", &metadata.Service{}, deploy.Spec.Template.Spec, nil)
+		require.NoError(t, err)
+		container := specMap["containers"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "{{- toYaml .Values.web.web.command | nindent 8 }}", container["command"])
+		assert.Equal(t, "{{- toYaml .Values.web.web.ports | nindent 8 }}", container["ports"])
+		assert.Equal(t, "{{- toYaml .Values.web.web.livenessProbe | nindent 10 }}", container["livenessProbe"])
+		assert.Equal(t, "{{- toYaml .Values.web.web.lifecycle | nindent 10 }}", container["lifecycle"])
+		assert.Equal(t, "{{ .Values.web.web.workingDir | quote }}", container["workingDir"])
+
+		web := values["web"].(map[string]interface{})["web"].(map[string]interface{})
+		assert.Equal(t, []interface{}{"nginx"}, web["command"])
+		assert.Equal(t, "/srv", web["workingDir"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"containerPort": int64(9443), "name": "webhook-server"}}, web["ports"])
+		period, _, _ := unstructured.NestedInt64(web, "livenessProbe", "periodSeconds")
+		assert.EqualValues(t, 20, period)
+		preStop, _, _ := unstructured.NestedStringSlice(web, "lifecycle", "preStop", "exec", "command")
+		assert.Equal(t, []string{"sleep", "5"}, preStop)
+	})
+	t.Run("webhook ports kept", func(t *testing.T) {
+		appMeta := metadata.New(config.Config{AddWebhookOption: true})
+		specMap, values, err := ProcessSpec("web", appMeta, deploy.Spec.Template.Spec, nil)
+		require.NoError(t, err)
+		container := specMap["containers"].([]interface{})[0].(map[string]interface{})
+		assert.IsType(t, []interface{}{}, container["ports"])
+		assert.NotContains(t, values["web"].(map[string]interface{})["web"], "ports")
+	})
+}
@@ -0,0 +1,136 @@
+package pod
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SpecIndent - indentation of the pod spec fields in Deployment template. Templates returned by ProcessSpec
+// use this indentation, see ShiftIndent.
+const SpecIndent = 6
+
+var nindentRegexp = regexp.MustCompile(`\| nindent (\d+)`)
+
+// ShiftIndent adds offset to nindent of templates in the given unstructured value.
+func ShiftIndent(value interface{}, offset int) interface{} {
+	if offset == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		return nindentRegexp.ReplaceAllStringFunc(v, func(s string) string {
+			n, _ := strconv.Atoi(nindentRegexp.FindStringSubmatch(s)[1])
+			return fmt.Sprintf("| nindent %d", n+offset)
+		})
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = ShiftIndent(v[k], offset)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = ShiftIndent(v[i], offset)
+		}
+	}
+	return value
+}
+
+// processScheduling moves pod scheduling and runtime options into values.
+// Label selectors of affinity terms and topology spread constraints matching the workload own pods are extended
+// with chart selector labels when rendered, see scopeTemplate.
+func processScheduling(objName string, appMeta helmify.AppMetadata, spec corev1.PodSpec, podLabels map[string]string, specMap map[string]interface{}, values helmify.Values) error {
+	if spec.Affinity != nil {
+		affinityMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec.Affinity)
+		if err != nil {
+			return fmt.Errorf("%w: unable to convert affinity to unstructured", err)
+		}
+		err = setScoped(objName, appMeta.ChartName(), podLabels, specMap, values, "affinity", affinityMap)
+		if err != nil {
+			return err
+		}
+	}
+	if spec.TopologySpreadConstraints != nil {
+		constraints := make([]interface{}, len(spec.TopologySpreadConstraints))
+		for i := range spec.TopologySpreadConstraints {
+			unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&spec.TopologySpreadConstraints[i])
+			if err != nil {
+				return fmt.Errorf("%w: unable to convert topology spread constraint to unstructured", err)
+			}
+			constraints[i] = unstr
+		}
+		err := setScoped(objName, appMeta.ChartName(), podLabels, specMap, values, "topologySpreadConstraints", constraints)
+		if err != nil {
+			return err
+		}
+	}
+
+	type scalar struct {
+		key   string
+		value interface{}
+	}
+	var scalars []scalar
+	if spec.PriorityClassName != "" {
+		scalars = append(scalars, scalar{key: "priorityClassName", value: spec.PriorityClassName})
+	}
+	if spec.RuntimeClassName != nil {
+		scalars = append(scalars, scalar{key: "runtimeClassName", value: *spec.RuntimeClassName})
+	}
+	if spec.HostNetwork {
+		scalars = append(scalars, scalar{key: "hostNetwork", value: spec.HostNetwork})
+	}
+	if spec.DNSPolicy != "" {
+		scalars = append(scalars, scalar{key: "dnsPolicy", value: string(spec.DNSPolicy)})
+	}
+	if spec.TerminationGracePeriodSeconds != nil {
+		scalars = append(scalars, scalar{key: "terminationGracePeriodSeconds", value: *spec.TerminationGracePeriodSeconds})
+	}
+	for _, s := range scalars {
+		tpl, err := values.Add(s.value, objName, s.key)
+		if err != nil {
+			return fmt.Errorf("%w: unable to set %s value", err, s.key)
+		}
+		if str, ok := s.value.(string); ok && strings.Contains(str, "{{") {
+			// templated name of the chart object, see refs.Graph
+			tpl = fmt.Sprintf("{{ tpl .Values.%s.%s $ | quote }}", objName, s.key)
+		}
+		specMap[s.key] = tpl
+	}
+	return nil
+}
+
+// setScoped moves unstructured value into values and replaces the pod spec field with the template.
+func setScoped(objName, chartName string, podLabels map[string]string, specMap map[string]interface{}, values helmify.Values, key string, value interface{}) error {
+	err := unstructured.SetNestedField(values, value, objName, key)
+	if err != nil {
+		return fmt.Errorf("%w: unable to set %s value", err, key)
+	}
+	specMap[key] = scopeTemplate(objName, chartName, key, podLabels)
+	return nil
+}
+
+// scopeTemplate returns template of affinity or topology spread constraints value. If podLabels are set, the value
+// is rendered with scopeSelectors helper generated in _helpers.tpl adding chart selector labels to label selectors
+// matching podLabels, so e.g. pod anti-affinity applies to pods of the same release only.
+func scopeTemplate(objName, chartName, key string, podLabels map[string]string) string {
+	if len(podLabels) == 0 {
+		return fmt.Sprintf(`{{- toYaml .Values.%s.%s | nindent 8 }}`, objName, key)
+	}
+	keys := make([]string, 0, len(podLabels))
+	for k := range podLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	labels := make([]string, 0, len(keys))
+	for _, k := range keys {
+		labels = append(labels, fmt.Sprintf("%q %q", k, podLabels[k]))
+	}
+	return fmt.Sprintf(`{{- include "%s.scopeSelectors" (dict "value" .Values.%s.%s "podLabels" (dict %s) "context" $) | nindent 8 }}`,
+		chartName, objName, key, strings.Join(labels, " "))
+}
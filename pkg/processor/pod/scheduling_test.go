@@ -0,0 +1,125 @@
+package pod
+
+import (
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const strScheduling = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      priorityClassName: my-app-critical
+      runtimeClassName: gvisor
+      hostNetwork: true
+      dnsPolicy: ClusterFirstWithHostNet
+      terminationGracePeriodSeconds: 15
+      affinity:
+        podAntiAffinity:
+          preferredDuringSchedulingIgnoredDuringExecution:
+          - weight: 100
+            podAffinityTerm:
+              topologyKey: kubernetes.io/hostname
+              labelSelector:
+                matchLabels:
+                  app: web
+          requiredDuringSchedulingIgnoredDuringExecution:
+          - topologyKey: zone
+            labelSelector:
+              matchLabels:
+                app: db
+      topologySpreadConstraints:
+      - maxSkew: 1
+        topologyKey: zone
+        whenUnsatisfiable: ScheduleAnyway
+        labelSelector:
+          matchLabels:
+            app: web
+      containers:
+      - name: web
+        image: nginx:1.25.1`
+
+func Test_processScheduling(t *testing.T) {
+	var deploy appsv1.Deployment
+	obj := internal.GenerateObj(strScheduling)
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deploy))
+	appMeta := metadata.New(config.Config{ChartName: "chart"})
+	appMeta.Load(obj)
+	appMeta.Load(internal.GenerateObj("apiVersion: scheduling.k8s.io/v1\nkind: PriorityClass\nmetadata:\n  name: my-app-critical"))
+	ownSelector := map[string]interface{}{"app": "web"}
+
+	t.Run("templates", func(t *testing.T) {
+		spec := deploy.Spec.Template.Spec
+		spec.PriorityClassName = appMeta.TemplatedName(spec.PriorityClassName)
+		specMap, _, err := ProcessSpec("web", appMeta, spec, deploy.Spec.Template.Labels)
+		require.NoError(t, err)
+		assert.Equal(t, `{{- include "chart.scopeSelectors" (dict "value" .Values.web.affinity "podLabels" (dict "app" "web") "context" $) | nindent 8 }}`, specMap["affinity"])
+		assert.Equal(t, `{{- include "chart.scopeSelectors" (dict "value" .Values.web.topologySpreadConstraints "podLabels" (dict "app" "web") "context" $) | nindent 8 }}`, specMap["topologySpreadConstraints"])
+		assert.Equal(t, "{{ tpl .Values.web.priorityClassName $ | quote }}", specMap["priorityClassName"])
+		assert.Equal(t, "{{ .Values.web.runtimeClassName | quote }}", specMap["runtimeClassName"])
+		assert.Equal(t, "{{ .Values.web.hostNetwork }}", specMap["hostNetwork"])
+		assert.Equal(t, "{{ .Values.web.dnsPolicy | quote }}", specMap["dnsPolicy"])
+		assert.Equal(t, "{{ .Values.web.terminationGracePeriodSeconds }}", specMap["terminationGracePeriodSeconds"])
+	})
+	t.Run("values", func(t *testing.T) {
+		_, values, err := ProcessSpec("web", appMeta, deploy.Spec.Template.Spec, deploy.Spec.Template.Labels)
+		require.NoError(t, err)
+		web := values["web"].(map[string]interface{})
+		assert.Equal(t, "my-app-critical", web["priorityClassName"])
+		assert.Equal(t, "gvisor", web["runtimeClassName"])
+		assert.Equal(t, true, web["hostNetwork"])
+		assert.Equal(t, "ClusterFirstWithHostNet", web["dnsPolicy"])
+		assert.EqualValues(t, 15, web["terminationGracePeriodSeconds"])
+
+		preferred, _, _ := unstructured.NestedSlice(web, "affinity", "podAntiAffinity", "preferredDuringSchedulingIgnoredDuringExecution")
+		require.Len(t, preferred, 1)
+		own, _, _ := unstructured.NestedMap(preferred[0].(map[string]interface{}), "podAffinityTerm", "labelSelector", "matchLabels")
+		assert.Equal(t, ownSelector, own)
+
+		required, _, _ := unstructured.NestedSlice(web, "affinity", "podAntiAffinity", "requiredDuringSchedulingIgnoredDuringExecution")
+		require.Len(t, required, 1)
+		other, _, _ := unstructured.NestedMap(required[0].(map[string]interface{}), "labelSelector", "matchLabels")
+		assert.Equal(t, map[string]interface{}{"app": "db"}, other, "selector of other pods must be kept")
+
+		constraints, _, _ := unstructured.NestedSlice(web, "topologySpreadConstraints")
+		require.Len(t, constraints, 1)
+		own, _, _ = unstructured.NestedMap(constraints[0].(map[string]interface{}), "labelSelector", "matchLabels")
+		assert.Equal(t, ownSelector, own)
+	})
+	t.Run("selectors kept without pod labels", func(t *testing.T) {
+		specMap, values, err := ProcessSpec("web", appMeta, deploy.Spec.Template.Spec, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "{{- toYaml .Values.web.topologySpreadConstraints | nindent 8 }}", specMap["topologySpreadConstraints"])
+		constraints, _, _ := unstructured.NestedSlice(values, "web", "topologySpreadConstraints")
+		require.Len(t, constraints, 1)
+		own, _, _ := unstructured.NestedMap(constraints[0].(map[string]interface{}), "labelSelector", "matchLabels")
+		assert.Equal(t, map[string]interface{}{"app": "web"}, own)
+	})
+}
+
+func Test_ShiftIndent(t *testing.T) {
+	value := map[string]interface{}{
+		"affinity":   "{{- tpl (toYaml .Values.web.affinity) $ | nindent 8 }}",
+		"containers": []interface{}{map[string]interface{}{"args": "{{- toYaml .Values.web.web.args | nindent 8 }}"}},
+		"dnsPolicy":  "{{ .Values.web.dnsPolicy | quote }}",
+	}
+	assert.Equal(t, map[string]interface{}{
+		"affinity":   "{{- tpl (toYaml .Values.web.affinity) $ | nindent 12 }}",
+		"containers": []interface{}{map[string]interface{}{"args": "{{- toYaml .Values.web.web.args | nindent 12 }}"}},
+		"dnsPolicy":  "{{ .Values.web.dnsPolicy | quote }}",
+	}, ShiftIndent(value, 4))
+}
@@ -59,7 +59,7 @@ func (r clusterRoleBinding) Process(appMeta helmify.AppMetadata, obj *unstructur
 	}
 
 	for i, s := range rb.Subjects {
-		s.Namespace = "{{ .Release.Namespace }}"
+		s.Namespace = subjectNamespace(appMeta, s.Namespace)
 		s.Name = appMeta.TemplatedName(s.Name)
 		rb.Subjects[i] = s
 	}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/processor"
 
 	"github.com/EdgeGamingGG/helmify/pkg/helmify"
@@ -57,7 +58,7 @@ func (r roleBinding) Process(appMeta helmify.AppMetadata, obj *unstructured.Unst
 	}
 
 	for i, s := range rb.Subjects {
-		s.Namespace = "{{ .Release.Namespace }}"
+		s.Namespace = subjectNamespace(appMeta, s.Namespace)
 		s.Name = appMeta.TemplatedName(s.Name)
 		rb.Subjects[i] = s
 	}
@@ -100,3 +101,15 @@ func (r *rbResult) Values() helmify.Values {
 func (r *rbResult) Write(writer io.Writer) error {
 	return roleBindingTempl.Execute(writer, r.data)
 }
+
+// subjectNamespace templates namespace of RoleBinding and ClusterRoleBinding subject.
+// Subjects are bound to release namespace unless namespace mode is set explicitly.
+func subjectNamespace(appMeta helmify.AppMetadata, ns string) string {
+	if appMeta.Config().Namespaces() == config.NamespaceOmit {
+		return "{{ .Release.Namespace }}"
+	}
+	if ns == "" {
+		return ""
+	}
+	return appMeta.TemplatedNamespace(ns)
+}
@@ -1,8 +1,10 @@
 package rbac
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/metadata"
 
 	"github.com/EdgeGamingGG/helmify/internal"
@@ -38,4 +40,20 @@ func Test_roleBinding_Process(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, false, processed)
 	})
+	t.Run("subject namespace", func(t *testing.T) {
+		for mode, expected := range map[config.NamespaceMode]string{
+			config.NamespaceOmit:     "namespace: '{{ .Release.Namespace }}'",
+			config.NamespacePreserve: "namespace: my-operator-system",
+			config.NamespaceValues:   "namespace: '{{ .Values.namespaces.myOperatorSystem }}'",
+		} {
+			obj := internal.GenerateObj(roleBindingYaml)
+			testMeta := metadata.New(config.Config{ChartName: "chart-name", NamespaceMode: mode})
+			testMeta.Load(obj)
+			_, tmpl, err := testInstance.Process(testMeta, obj)
+			assert.NoError(t, err)
+			buf := bytes.Buffer{}
+			assert.NoError(t, tmpl.Write(&buf))
+			assert.Contains(t, buf.String(), expected, mode)
+		}
+	})
 }
@@ -0,0 +1,289 @@
+package rollout
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/processor"
+	"github.com/EdgeGamingGG/helmify/pkg/processor/pod"
+	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var rolloutGK = schema.GroupKind{
+	Group: "argoproj.io",
+	Kind:  "Rollout",
+}
+
+var rolloutTempl, _ = template.New("rollout").Parse(
+	`{{- .Meta }}
+spec:
+{{- if .Replicas }}
+{{ .Replicas }}
+{{- end }}
+{{- if .Strategy }}
+{{ .Strategy }}
+{{- end }}
+{{- if .Spec }}
+{{ .Spec }}
+{{- end }}
+{{- if .Selector }}
+  selector:
+{{ .Selector }}
+{{- end }}
+{{- if .PodSpec }}
+  template:
+    metadata:
+      labels:
+{{ .PodLabels }}
+{{- .PodAnnotations }}
+    spec:
+{{ .PodSpec }}
+{{- end }}`)
+
+const selectorTempl = `%[1]s
+{{- include "%[2]s.selectorLabels" . | nindent 6 }}
+%[3]s`
+
+// New creates processor for Argo Rollouts Rollout resource.
+func New() helmify.Processor {
+	return &rollout{}
+}
+
+type rollout struct{}
+
+// Process Rollout object into template. Returns false if not capable of processing given resource type.
+// Pod template is processed the same way as for Deployment.
+func (r rollout) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind().GroupKind() != rolloutGK {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	specMap, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, fmt.Errorf("%w: unable to read rollout spec", err)
+	}
+	values := helmify.Values{}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	res := &result{values: values, schema: helmify.Schema{}}
+	res.data.Meta = meta
+
+	if replicas, ok := specMap["replicas"].(int64); ok {
+		replicasTpl, err := values.Add(replicas, nameCamel, "replicas")
+		if err != nil {
+			return true, nil, err
+		}
+		res.data.Replicas = "  replicas: " + replicasTpl
+		if appMeta.Autoscaled(obj.GetKind(), obj.GetName()) {
+			// replicas are managed by HorizontalPodAutoscaler when autoscaling is enabled
+			res.data.Replicas = fmt.Sprintf("  {{- if not .Values.%s.autoscaling.enabled }}\n%s\n  {{- end }}", nameCamel, res.data.Replicas)
+		}
+		res.schema.Add("minimum", 0, nameCamel, "replicas")
+	}
+	delete(specMap, "replicas")
+
+	if strategy, ok := specMap["strategy"].(map[string]interface{}); ok {
+		res.data.Strategy, err = processStrategy(appMeta, nameCamel, strategy, values)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	delete(specMap, "strategy")
+
+	if selector, ok := specMap["selector"].(map[string]interface{}); ok {
+		res.data.Selector, err = processSelector(appMeta, selector)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	delete(specMap, "selector")
+
+	if podTemplate, ok := specMap["template"].(map[string]interface{}); ok {
+		err = processPodTemplate(appMeta, nameCamel, podTemplate, res)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	delete(specMap, "template")
+
+	if revisionHistoryLimit, ok := specMap["revisionHistoryLimit"].(int64); ok {
+		specMap["revisionHistoryLimit"], err = values.Add(revisionHistoryLimit, nameCamel, "revisionHistoryLimit")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	if workloadName, ok, _ := unstructured.NestedString(specMap, "workloadRef", "name"); ok {
+		err = unstructured.SetNestedField(specMap, appMeta.TemplatedName(workloadName), "workloadRef", "name")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	if len(specMap) != 0 {
+		res.data.Spec, err = yamlformat.Marshal(specMap, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		res.data.Spec = strings.ReplaceAll(res.data.Spec, "'", "")
+	}
+	return true, res, nil
+}
+
+// processStrategy templates canary and blueGreen strategy parameters.
+func processStrategy(appMeta helmify.AppMetadata, name string, strategy map[string]interface{}, values helmify.Values) (string, error) {
+	if canary, ok := strategy["canary"].(map[string]interface{}); ok {
+		templateNames(appMeta, canary, "canaryService", "stableService")
+		if stableIngress, ok, _ := unstructured.NestedString(canary, "trafficRouting", "nginx", "stableIngress"); ok {
+			err := unstructured.SetNestedField(canary, appMeta.TemplatedName(stableIngress), "trafficRouting", "nginx", "stableIngress")
+			if err != nil {
+				return "", err
+			}
+		}
+		if steps, ok := canary["steps"].([]interface{}); ok {
+			err := unstructured.SetNestedSlice(values, steps, name, "strategy", "canary", "steps")
+			if err != nil {
+				return "", fmt.Errorf("%w: unable to set canary steps value", err)
+			}
+			canary["steps"] = fmt.Sprintf("{{- toYaml .Values.%s.strategy.canary.steps | nindent 8 }}", name)
+		}
+		err := addValues(canary, values, name, "canary", "maxSurge", "maxUnavailable")
+		if err != nil {
+			return "", err
+		}
+	}
+	if blueGreen, ok := strategy["blueGreen"].(map[string]interface{}); ok {
+		templateNames(appMeta, blueGreen, "activeService", "previewService")
+		err := addValues(blueGreen, values, name, "blueGreen", "autoPromotionEnabled", "autoPromotionSeconds", "scaleDownDelaySeconds")
+		if err != nil {
+			return "", err
+		}
+	}
+	res, err := yamlformat.Marshal(map[string]interface{}{"strategy": strategy}, 2)
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(res, "'", ""), nil
+}
+
+func templateNames(appMeta helmify.AppMetadata, obj map[string]interface{}, fields ...string) {
+	for _, field := range fields {
+		if name, ok := obj[field].(string); ok {
+			obj[field] = appMeta.TemplatedName(name)
+		}
+	}
+}
+
+func addValues(obj map[string]interface{}, values helmify.Values, name, strategyType string, fields ...string) error {
+	for _, field := range fields {
+		value, ok := obj[field]
+		if !ok {
+			continue
+		}
+		tpl, err := values.Add(value, name, "strategy", strategyType, field)
+		if err != nil {
+			return err
+		}
+		obj[field] = tpl
+	}
+	return nil
+}
+
+func processSelector(appMeta helmify.AppMetadata, selector map[string]interface{}) (string, error) {
+	matchLabels, err := yamlformat.Marshal(map[string]interface{}{"matchLabels": selector["matchLabels"]}, 0)
+	if err != nil {
+		return "", err
+	}
+	matchExpr := ""
+	if expr, ok := selector["matchExpressions"]; ok {
+		matchExpr, err = yamlformat.Marshal(map[string]interface{}{"matchExpressions": expr}, 0)
+		if err != nil {
+			return "", err
+		}
+	}
+	res := fmt.Sprintf(selectorTempl, matchLabels, appMeta.ChartName(), matchExpr)
+	res = strings.Trim(res, " \n")
+	return string(yamlformat.Indent([]byte(res), 4)), nil
+}
+
+func processPodTemplate(appMeta helmify.AppMetadata, name string, podTemplate map[string]interface{}, res *result) error {
+	tpl := corev1.PodTemplateSpec{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(podTemplate, &tpl)
+	if err != nil {
+		return fmt.Errorf("%w: unable to cast rollout template to pod template", err)
+	}
+	podMeta, err := pod.TemplateMeta(name, appMeta, tpl.ObjectMeta, res.values)
+	if err != nil {
+		return err
+	}
+	res.data.PodLabels, err = yamlformat.Marshal(podMeta["labels"], 8)
+	if err != nil {
+		return err
+	}
+	res.data.PodLabels = pod.RenderDefaults(res.data.PodLabels)
+	res.data.PodLabels += fmt.Sprintf("\n      {{- include \"%s.selectorLabels\" . | nindent 8 }}", appMeta.ChartName())
+	delete(podMeta, "labels")
+	if len(podMeta) != 0 {
+		res.data.PodAnnotations, err = yamlformat.Marshal(podMeta, 6)
+		if err != nil {
+			return err
+		}
+		res.data.PodAnnotations = "\n" + pod.RenderDefaults(res.data.PodAnnotations)
+	}
+	specMap, podValues, err := pod.ProcessSpec(name, appMeta, tpl.Spec, tpl.Labels)
+	if err != nil {
+		return err
+	}
+	err = res.values.Merge(podValues)
+	if err != nil {
+		return err
+	}
+	res.data.PodSpec, err = yamlformat.Marshal(specMap, 6)
+	if err != nil {
+		return err
+	}
+	res.data.PodSpec = strings.ReplaceAll(res.data.PodSpec, "'", "")
+	res.data.PodSpec = pod.RenderDefaults(res.data.PodSpec)
+	res.schema.Merge(pod.Schema(name, tpl.Spec))
+	return nil
+}
+
+type result struct {
+	data struct {
+		Meta           string
+		Replicas       string
+		Strategy       string
+		Spec           string
+		Selector       string
+		PodLabels      string
+		PodAnnotations string
+		PodSpec        string
+	}
+	values helmify.Values
+	schema helmify.Schema
+}
+
+func (r *result) Filename() string {
+	return "rollout.yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Schema() helmify.Schema {
+	return r.schema
+}
+
+func (r *result) Write(writer io.Writer) error {
+	return rolloutTempl.Execute(writer, r.data)
+}
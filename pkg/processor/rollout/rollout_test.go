@@ -0,0 +1,175 @@
+package rollout
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	strCanary = `apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: my-operator-web
+  namespace: my-operator-system
+spec:
+  replicas: 3
+  revisionHistoryLimit: 2
+  selector:
+    matchLabels:
+      app: web
+  strategy:
+    canary:
+      canaryService: my-operator-canary
+      stableService: my-operator-stable
+      maxSurge: 25%
+      steps:
+      - setWeight: 20
+      - pause: {duration: 1h}
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25.1
+`
+	strBlueGreen = `apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: my-operator-web
+  namespace: my-operator-system
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: web
+  strategy:
+    blueGreen:
+      activeService: my-operator-active
+      previewService: my-operator-preview
+      autoPromotionEnabled: false
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25.1
+`
+	strWorkloadRef = `apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: my-operator-web
+  namespace: my-operator-system
+spec:
+  replicas: 2
+  workloadRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: my-operator-web
+  strategy:
+    canary:
+      steps:
+      - setWeight: 50
+`
+)
+
+func newTestMeta(objs ...string) *metadata.Service {
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	for _, obj := range objs {
+		testMeta.Load(internal.GenerateObj(obj))
+	}
+	return testMeta
+}
+
+func service(name string) string {
+	return `apiVersion: v1
+kind: Service
+metadata:
+  name: ` + name
+}
+
+func Test_rollout_Process(t *testing.T) {
+	var testInstance rollout
+
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := testInstance.Process(&metadata.Service{}, internal.TestNs)
+		require.NoError(t, err)
+		assert.False(t, processed)
+	})
+	t.Run("canary", func(t *testing.T) {
+		testMeta := newTestMeta(strCanary, service("my-operator-canary"), service("my-operator-stable"))
+		processed, tmpl, err := testInstance.Process(testMeta, internal.GenerateObj(strCanary))
+		require.NoError(t, err)
+		assert.True(t, processed)
+		assert.Equal(t, "rollout.yaml", tmpl.Filename())
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		out := buf.String()
+		assert.Contains(t, out, "replicas: {{ .Values.web.replicas }}")
+		assert.Contains(t, out, "revisionHistoryLimit: {{ .Values.web.revisionHistoryLimit }}")
+		assert.Contains(t, out, `canaryService: {{ include "chart-name.fullname" . }}-canary`)
+		assert.Contains(t, out, `stableService: {{ include "chart-name.fullname" . }}-stable`)
+		assert.Contains(t, out, "maxSurge: {{ .Values.web.strategy.canary.maxSurge | quote }}")
+		assert.Contains(t, out, "steps: {{- toYaml .Values.web.strategy.canary.steps | nindent 8 }}")
+		assert.Contains(t, out, `{{- include "chart-name.selectorLabels" . | nindent 6 }}`)
+		assert.Contains(t, out, `{{- include "chart-name.selectorLabels" . | nindent 8 }}`)
+		assert.Contains(t, out, "image: {{ .Values.web.web.image.repository }}")
+
+		values := tmpl.Values()
+		assert.EqualValues(t, 3, values["web"].(map[string]interface{})["replicas"])
+		steps, _, _ := unstructured.NestedSlice(values, "web", "strategy", "canary", "steps")
+		assert.Len(t, steps, 2)
+	})
+	t.Run("blue green", func(t *testing.T) {
+		testMeta := newTestMeta(strBlueGreen, service("my-operator-active"), service("my-operator-preview"))
+		_, tmpl, err := testInstance.Process(testMeta, internal.GenerateObj(strBlueGreen))
+		require.NoError(t, err)
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		out := buf.String()
+		assert.Contains(t, out, `activeService: {{ include "chart-name.fullname" . }}-active`)
+		assert.Contains(t, out, `previewService: {{ include "chart-name.fullname" . }}-preview`)
+		assert.Contains(t, out, "autoPromotionEnabled: {{ .Values.web.strategy.blueGreen.autoPromotionEnabled")
+	})
+	t.Run("replicas omitted when autoscaling enabled", func(t *testing.T) {
+		testMeta := newTestMeta(strBlueGreen, service("my-operator-svc"), `apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: my-operator-web
+spec:
+  scaleTargetRef:
+    apiVersion: argoproj.io/v1alpha1
+    kind: Rollout
+    name: my-operator-web`)
+		_, tmpl, err := testInstance.Process(testMeta, internal.GenerateObj(strBlueGreen))
+		require.NoError(t, err)
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		assert.Contains(t, buf.String(), "  {{- if not .Values.web.autoscaling.enabled }}\n  replicas: {{ .Values.web.replicas }}\n  {{- end }}")
+	})
+	t.Run("workload ref", func(t *testing.T) {
+		testMeta := newTestMeta(strWorkloadRef, service("my-operator-svc"))
+		_, tmpl, err := testInstance.Process(testMeta, internal.GenerateObj(strWorkloadRef))
+		require.NoError(t, err)
+
+		buf := bytes.Buffer{}
+		require.NoError(t, tmpl.Write(&buf))
+		out := buf.String()
+		assert.Contains(t, out, `name: {{ include "chart-name.fullname" . }}-web`)
+		assert.NotContains(t, out, "template:")
+		assert.NotContains(t, out, "selector:")
+	})
+}
@@ -70,6 +70,7 @@ func (d secret) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructu
 	}
 
 	values := helmify.Values{}
+	schema := helmify.Schema{}
 	var data, stringData string
 	templatedData := map[string]string{}
 	for key := range sec.Data {
@@ -81,6 +82,7 @@ func (d secret) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructu
 		if err != nil {
 			return true, nil, fmt.Errorf("%w: unable add secret to values", err)
 		}
+		schema.Require(nameCamelCase, keyCamelCase)
 		templatedData[key] = templatedName
 	}
 	if len(templatedData) != 0 {
@@ -102,6 +104,7 @@ func (d secret) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructu
 		if err != nil {
 			return true, nil, fmt.Errorf("%w: unable add secret to values", err)
 		}
+		schema.Require(nameCamelCase, keyCamelCase)
 		templatedData[key] = templatedName
 	}
 	if len(templatedData) != 0 {
@@ -122,6 +125,7 @@ func (d secret) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructu
 			StringData string
 		}{Type: secretType, Meta: meta, Data: data, StringData: stringData},
 		values: values,
+		schema: schema,
 	}, nil
 }
 
@@ -134,6 +138,7 @@ type result struct {
 		StringData string
 	}
 	values helmify.Values
+	schema helmify.Schema
 }
 
 func (r *result) Filename() string {
@@ -144,6 +149,10 @@ func (r *result) Values() helmify.Values {
 	return r.values
 }
 
+func (r *result) Schema() helmify.Schema {
+	return r.schema
+}
+
 func (r *result) Write(writer io.Writer) error {
 	return secretTempl.Execute(writer, r.data)
 }
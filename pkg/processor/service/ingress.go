@@ -2,20 +2,54 @@ package service
 
 import (
 	"fmt"
+	"io"
+
 	"github.com/EdgeGamingGG/helmify/pkg/helmify"
 	"github.com/EdgeGamingGG/helmify/pkg/processor"
 	yamlformat "github.com/EdgeGamingGG/helmify/pkg/yaml"
-	"io"
+	"github.com/iancoleman/strcase"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"text/template"
 )
 
-var ingressTempl, _ = template.New("ingress").Parse(
-	`{{ .Meta }}
-{{ .Spec }}`)
+// ingressTempl renders Ingress from the standard Helm values block: enabled, className, annotations, hosts and tls.
+// Backends are rendered with tpl because default values contain templated service names.
+const ingressTempl = `{{- if .Values.%[1]s.enabled }}
+%[2]s
+  {{- with .Values.%[1]s.annotations }}
+  annotations:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+spec:
+  {{- with .Values.%[1]s.className }}
+  ingressClassName: {{ . }}
+  {{- end }}%[3]s
+  {{- with .Values.%[1]s.tls }}
+  tls:
+    {{- tpl (toYaml .) $ | nindent 4 }}
+  {{- end }}
+  rules:
+    {{- range .Values.%[1]s.hosts }}
+    -
+      {{- with .host }}
+      host: {{ . | quote }}
+      {{- end }}
+      {{- with .paths }}
+      http:
+        paths:
+          {{- range . }}
+          - path: {{ .path | quote }}
+            {{- with .pathType }}
+            pathType: {{ . }}
+            {{- end }}
+            backend:
+              {{- tpl (toYaml .backend) $ | nindent 14 }}
+          {{- end }}
+      {{- end }}
+    {{- end }}
+{{- end }}`
 
 var ingressGVC = schema.GroupVersionKind{
 	Group:   "networking.k8s.io",
@@ -30,7 +64,7 @@ func NewIngress() helmify.Processor {
 
 type ingress struct{}
 
-// Process k8s Service object into template. Returns false if not capable of processing given resource type.
+// Process k8s Ingress object into template. Returns false if not capable of processing given resource type.
 func (r ingress) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
 	if obj.GroupVersionKind() != ingressGVC {
 		return false, nil, nil
@@ -40,23 +74,39 @@ func (r ingress) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstruct
 	if err != nil {
 		return true, nil, fmt.Errorf("%w: unable to cast to ingress", err)
 	}
-	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	// annotations are rendered from values
+	metaObj := obj.DeepCopy()
+	metaObj.SetAnnotations(nil)
+	meta, err := processor.ProcessObjMeta(appMeta, metaObj)
 	if err != nil {
 		return true, nil, err
 	}
 	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
 	processIngressSpec(appMeta, &ing.Spec)
-	spec, err := yamlformat.Marshal(map[string]interface{}{"spec": &ing.Spec}, 0)
+
+	values, err := processIngressValues(nameCamel, &ing)
 	if err != nil {
 		return true, nil, err
 	}
 
+	defaultBackend := ""
+	if ing.Spec.DefaultBackend != nil {
+		backend, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ing.Spec.DefaultBackend)
+		if err != nil {
+			return true, nil, fmt.Errorf("%w: unable to convert ingress default backend to unstructured", err)
+		}
+		defaultBackend, err = yamlformat.MarshalTemplated(map[string]interface{}{"defaultBackend": backend}, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		defaultBackend = "\n" + defaultBackend
+	}
+
 	return true, &ingressResult{
-		name: name + ".yaml",
-		data: struct {
-			Meta string
-			Spec string
-		}{Meta: meta, Spec: spec},
+		name:   name + ".yaml",
+		data:   fmt.Sprintf(ingressTempl, nameCamel, meta, defaultBackend),
+		values: values,
 	}, nil
 }
 
@@ -75,12 +125,79 @@ func processIngressSpec(appMeta helmify.AppMetadata, ing *networkingv1.IngressSp
 	}
 }
 
-type ingressResult struct {
-	name string
-	data struct {
-		Meta string
-		Spec string
+// processIngressValues returns ingress values block with the original manifest data as defaults.
+func processIngressValues(name string, ing *networkingv1.Ingress) (helmify.Values, error) {
+	values := helmify.Values{}
+	_, err := values.Add(true, name, "enabled")
+	if err != nil {
+		return nil, err
+	}
+	className := ""
+	if ing.Spec.IngressClassName != nil {
+		className = *ing.Spec.IngressClassName
+	}
+	_, err = values.Add(className, name, "className")
+	if err != nil {
+		return nil, err
+	}
+	annotations := map[string]interface{}{}
+	for k, v := range ing.GetAnnotations() {
+		annotations[k] = v
+	}
+	err = unstructured.SetNestedMap(values, annotations, name, "annotations")
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to set ingress annotations value", err)
+	}
+
+	hosts := make([]interface{}, len(ing.Spec.Rules))
+	for i, rule := range ing.Spec.Rules {
+		host := map[string]interface{}{}
+		if rule.Host != "" {
+			host["host"] = rule.Host
+		}
+		if rule.HTTP != nil {
+			paths := make([]interface{}, len(rule.HTTP.Paths))
+			for j, p := range rule.HTTP.Paths {
+				backend, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&rule.HTTP.Paths[j].Backend)
+				if err != nil {
+					return nil, fmt.Errorf("%w: unable to convert ingress backend to unstructured", err)
+				}
+				path := map[string]interface{}{
+					"path":    p.Path,
+					"backend": backend,
+				}
+				if p.PathType != nil {
+					path["pathType"] = string(*p.PathType)
+				}
+				paths[j] = path
+			}
+			host["paths"] = paths
+		}
+		hosts[i] = host
+	}
+	err = unstructured.SetNestedSlice(values, hosts, name, "hosts")
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to set ingress hosts value", err)
 	}
+
+	tls := make([]interface{}, len(ing.Spec.TLS))
+	for i := range ing.Spec.TLS {
+		tls[i], err = runtime.DefaultUnstructuredConverter.ToUnstructured(&ing.Spec.TLS[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to convert ingress tls to unstructured", err)
+		}
+	}
+	err = unstructured.SetNestedSlice(values, tls, name, "tls")
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to set ingress tls value", err)
+	}
+	return values, nil
+}
+
+type ingressResult struct {
+	name   string
+	data   string
+	values helmify.Values
 }
 
 func (r *ingressResult) Filename() string {
@@ -88,9 +205,10 @@ func (r *ingressResult) Filename() string {
 }
 
 func (r *ingressResult) Values() helmify.Values {
-	return helmify.Values{}
+	return r.values
 }
 
 func (r *ingressResult) Write(writer io.Writer) error {
-	return ingressTempl.Execute(writer, r.data)
+	_, err := writer.Write([]byte(r.data))
+	return err
 }
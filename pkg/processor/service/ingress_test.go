@@ -1,8 +1,12 @@
 package service
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+
 	"github.com/EdgeGamingGG/helmify/pkg/metadata"
 
 	"github.com/EdgeGamingGG/helmify/internal"
@@ -36,6 +40,47 @@ func Test_ingress_Process(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, true, processed)
 	})
+	t.Run("values", func(t *testing.T) {
+		obj := internal.GenerateObj(ingressYaml)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(obj)
+		testMeta.Load(internal.GenerateObj(svcYaml))
+		_, tt, err := testInstance.Process(testMeta, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, helmify.Values{
+			"appIngress": map[string]interface{}{
+				"enabled":   true,
+				"className": "",
+				"annotations": map[string]interface{}{
+					"nginx.ingress.kubernetes.io/rewrite-target": "/",
+				},
+				"hosts": []interface{}{
+					map[string]interface{}{
+						"paths": []interface{}{
+							map[string]interface{}{
+								"path":     "/testpath",
+								"pathType": "Prefix",
+								"backend": map[string]interface{}{
+									"service": map[string]interface{}{
+										"name": "myapp-service",
+										"port": map[string]interface{}{"number": int64(8443)},
+									},
+								},
+							},
+						},
+					},
+				},
+				"tls": []interface{}{},
+			},
+		}, tt.Values())
+		buf := bytes.Buffer{}
+		assert.NoError(t, tt.Write(&buf))
+		assert.NotContains(t, buf.String(), "rewrite-target")
+		assert.Contains(t, buf.String(), "{{- range .Values.appIngress.hosts }}")
+		assert.Contains(t, buf.String(), "- path: {{ .path | quote }}")
+		assert.Equal(t, map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/"}, obj.GetAnnotations(),
+			"processed object must not be changed")
+	})
 	t.Run("skipped", func(t *testing.T) {
 		obj := internal.TestNs
 		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
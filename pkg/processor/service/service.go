@@ -79,6 +79,13 @@ func (r svc) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 		svcType = corev1.ServiceTypeClusterIP
 	}
 	_ = unstructured.SetNestedField(values, string(svcType), shortNameCamel, "type")
+	schema := helmify.Schema{}
+	schema.Add("enum", []string{
+		string(corev1.ServiceTypeClusterIP),
+		string(corev1.ServiceTypeNodePort),
+		string(corev1.ServiceTypeLoadBalancer),
+		string(corev1.ServiceTypeExternalName),
+	}, shortNameCamel, "type")
 	ports := make([]interface{}, len(service.Spec.Ports))
 	for i, p := range service.Spec.Ports {
 		pMap := map[string]interface{}{
@@ -113,6 +120,7 @@ func (r svc) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 		name:   shortName,
 		data:   res,
 		values: values,
+		schema: schema,
 	}, nil
 }
 
@@ -132,6 +140,7 @@ type result struct {
 	name   string
 	data   string
 	values helmify.Values
+	schema helmify.Schema
 }
 
 func (r *result) Filename() string {
@@ -142,6 +151,10 @@ func (r *result) Values() helmify.Values {
 	return r.values
 }
 
+func (r *result) Schema() helmify.Schema {
+	return r.schema
+}
+
 func (r *result) Write(writer io.Writer) error {
 	_, err := writer.Write([]byte(r.data))
 	return err
@@ -79,20 +79,8 @@ func (d statefulset) Process(appMeta helmify.AppMetadata, obj *unstructured.Unst
 	for i, claim := range ssSpec.VolumeClaimTemplates {
 		volName := claim.ObjectMeta.Name
 		delete(((ssSpecMap["volumeClaimTemplates"].([]interface{}))[i]).(map[string]interface{}), "status")
-		if claim.Spec.StorageClassName != nil {
-			scName := appMeta.TemplatedName(*claim.Spec.StorageClassName)
-			err = unstructured.SetNestedField(((ssSpecMap["volumeClaimTemplates"].([]interface{}))[i]).(map[string]interface{}), scName, "spec", "storageClassName")
-			if err != nil {
-				return true, nil, err
-			}
-		}
-		if claim.Spec.VolumeName != "" {
-			vName := appMeta.TemplatedName(claim.Spec.VolumeName)
-			err = unstructured.SetNestedField(((ssSpecMap["volumeClaimTemplates"].([]interface{}))[i]).(map[string]interface{}), vName, "spec", "volumeName")
-			if err != nil {
-				return true, nil, err
-			}
-		}
+		// storageClassName and volumeName reference cluster objects, they are templated only if presented in the chart
+		// by the reference rewriting, see refs.Graph.
 
 		resMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&claim.Spec.Resources)
 		if err != nil {
@@ -109,7 +97,7 @@ func (d statefulset) Process(appMeta helmify.AppMetadata, obj *unstructured.Unst
 	}
 
 	// process pod spec:
-	podSpecMap, podValues, err := pod.ProcessSpec(nameCamel, appMeta, ssSpec.Template.Spec)
+	podSpecMap, podValues, err := pod.ProcessSpec(nameCamel, appMeta, ssSpec.Template.Spec, nil)
 	if err != nil {
 		return true, nil, err
 	}
@@ -121,15 +109,26 @@ func (d statefulset) Process(appMeta helmify.AppMetadata, obj *unstructured.Unst
 	if err != nil {
 		return true, nil, err
 	}
+	err = pod.ProcessMetaDefaults(nameCamel, appMeta, (ssSpecMap["template"].(map[string]interface{}))["metadata"].(map[string]interface{}), values)
+	if err != nil {
+		return true, nil, err
+	}
 
 	spec, err := yamlformat.Marshal(ssSpecMap, 2)
 	if err != nil {
 		return true, nil, err
 	}
 	spec = strings.ReplaceAll(spec, "'", "")
+	spec = pod.RenderDefaults(spec)
+
+	schema := pod.Schema(nameCamel, ssSpec.Template.Spec)
+	if ssSpec.Replicas != nil {
+		schema.Add("minimum", 0, nameCamel, "replicas")
+	}
 
 	return true, &result{
 		values: values,
+		schema: schema,
 		data: struct {
 			Meta string
 			Spec string
@@ -146,6 +145,7 @@ type result struct {
 		Spec string
 	}
 	values helmify.Values
+	schema helmify.Schema
 }
 
 func (r *result) Filename() string {
@@ -156,12 +156,16 @@ func (r *result) Values() helmify.Values {
 	return r.values
 }
 
+func (r *result) Schema() helmify.Schema {
+	return r.schema
+}
+
 func (r *result) Write(writer io.Writer) error {
 	return statefulsetTempl.Execute(writer, r.data)
 }
 
 func ProcessSpec(objName string, appMeta helmify.AppMetadata, spec appsv1.StatefulSetSpec) (map[string]interface{}, helmify.Values, error) {
-	podSpecMap, podValues, err := pod.ProcessSpec(objName, appMeta, spec.Template.Spec)
+	podSpecMap, podValues, err := pod.ProcessSpec(objName, appMeta, spec.Template.Spec, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -196,13 +200,6 @@ func ProcessSpec(objName string, appMeta helmify.AppMetadata, spec appsv1.Statef
 				}
 			}
 
-			// Template storage class name if present
-			if spec, ok := pvcMap["spec"].(map[string]interface{}); ok {
-				if storageClassName, ok := spec["storageClassName"].(string); ok {
-					spec["storageClassName"] = appMeta.TemplatedName(storageClassName)
-				}
-			}
-
 			err = unstructured.SetNestedField(podValues, pvcMap, objName, "volumeClaimTemplates", pvcName)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%w: unable to set PVC template value", err)
@@ -226,8 +223,8 @@ func ProcessSpec(objName string, appMeta helmify.AppMetadata, spec appsv1.Statef
 			vctName := vctMap["metadata"].(map[string]interface{})["name"].(string)
 			vctNameCamel := strcase.ToLowerCamel(vctName)
 
-			// Replace volume claim template with template
-			templatedVcts[i] = fmt.Sprintf(`{{- toYaml .Values.%s.volumeClaimTemplates.%s | nindent 8 }}`, objName, vctNameCamel)
+			// Replace volume claim template with template. Rendered with tpl because values may contain templated names.
+			templatedVcts[i] = fmt.Sprintf(`{{- tpl (toYaml .Values.%s.volumeClaimTemplates.%s) $ | nindent 8 }}`, objName, vctNameCamel)
 		}
 		specMap["volumeClaimTemplates"] = templatedVcts
 	}
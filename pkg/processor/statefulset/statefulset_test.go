@@ -60,21 +60,21 @@ func TestProcessSpec_VolumeClaimTemplates(t *testing.T) {
 					"spec": map[string]interface{}{
 						"containers": []interface{}{
 							map[string]interface{}{
-								"env": []interface{}{
-									map[string]interface{}{
-										"name":  "KUBERNETES_CLUSTER_DOMAIN",
-										"value": "{{ quote .Values.kubernetesClusterDomain }}",
-									},
-								},
-								"image":     "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
-								"name":      "test-container",
-								"resources": map[string]interface{}{},
+								"helmifyDefault.env":          "test.testContainer.extraEnv",
+								"image":                       "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
+								"name":                        "test-container",
+								"resources":                   map[string]interface{}{},
+								"helmifyDefault.envFrom":      "test.testContainer.extraEnvFrom",
+								"helmifyDefault.volumeMounts": "test.testContainer.extraVolumeMounts",
 							},
+							map[string]interface{}{"helmifyDefaultEntries": "test.extraContainers"},
 						},
+						"helmifyDefault.volumes":        "test.extraVolumes",
+						"helmifyDefault.initContainers": "test.extraInitContainers",
 					},
 				},
 				"updateStrategy":       map[string]interface{}{},
-				"volumeClaimTemplates": []interface{}{"{{- toYaml .Values.test.volumeClaimTemplates.data | nindent 8 }}"},
+				"volumeClaimTemplates": []interface{}{"{{- tpl (toYaml .Values.test.volumeClaimTemplates.data) $ | nindent 8 }}"},
 			},
 			expectedPodValues: map[string]interface{}{
 				"test": map[string]interface{}{
@@ -83,6 +83,9 @@ func TestProcessSpec_VolumeClaimTemplates(t *testing.T) {
 							"repository": "nginx",
 							"tag":        "1.14.2",
 						},
+						"extraEnv":          []interface{}{},
+						"extraEnvFrom":      []interface{}{},
+						"extraVolumeMounts": []interface{}{},
 					},
 					"volumeClaimTemplates": map[string]interface{}{
 						"data": map[string]interface{}{
@@ -99,6 +102,9 @@ func TestProcessSpec_VolumeClaimTemplates(t *testing.T) {
 							},
 						},
 					},
+					"extraVolumes":        []interface{}{},
+					"extraContainers":     []interface{}{},
+					"extraInitContainers": []interface{}{},
 				},
 			},
 		},
@@ -142,21 +148,21 @@ func TestProcessSpec_VolumeClaimTemplates(t *testing.T) {
 					"spec": map[string]interface{}{
 						"containers": []interface{}{
 							map[string]interface{}{
-								"env": []interface{}{
-									map[string]interface{}{
-										"name":  "KUBERNETES_CLUSTER_DOMAIN",
-										"value": "{{ quote .Values.kubernetesClusterDomain }}",
-									},
-								},
-								"image":     "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
-								"name":      "test-container",
-								"resources": map[string]interface{}{},
+								"helmifyDefault.env":          "test.testContainer.extraEnv",
+								"image":                       "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
+								"name":                        "test-container",
+								"resources":                   map[string]interface{}{},
+								"helmifyDefault.envFrom":      "test.testContainer.extraEnvFrom",
+								"helmifyDefault.volumeMounts": "test.testContainer.extraVolumeMounts",
 							},
+							map[string]interface{}{"helmifyDefaultEntries": "test.extraContainers"},
 						},
+						"helmifyDefault.volumes":        "test.extraVolumes",
+						"helmifyDefault.initContainers": "test.extraInitContainers",
 					},
 				},
 				"updateStrategy":       map[string]interface{}{},
-				"volumeClaimTemplates": []interface{}{"{{- toYaml .Values.test.volumeClaimTemplates.data | nindent 8 }}"},
+				"volumeClaimTemplates": []interface{}{"{{- tpl (toYaml .Values.test.volumeClaimTemplates.data) $ | nindent 8 }}"},
 			},
 			expectedPodValues: map[string]interface{}{
 				"test": map[string]interface{}{
@@ -165,6 +171,9 @@ func TestProcessSpec_VolumeClaimTemplates(t *testing.T) {
 							"repository": "nginx",
 							"tag":        "1.14.2",
 						},
+						"extraEnv":          []interface{}{},
+						"extraEnvFrom":      []interface{}{},
+						"extraVolumeMounts": []interface{}{},
 					},
 					"volumeClaimTemplates": map[string]interface{}{
 						"data": map[string]interface{}{
@@ -182,6 +191,9 @@ func TestProcessSpec_VolumeClaimTemplates(t *testing.T) {
 							},
 						},
 					},
+					"extraVolumes":        []interface{}{},
+					"extraContainers":     []interface{}{},
+					"extraInitContainers": []interface{}{},
 				},
 			},
 		},
@@ -238,23 +250,23 @@ func TestProcessSpec_VolumeClaimTemplates(t *testing.T) {
 					"spec": map[string]interface{}{
 						"containers": []interface{}{
 							map[string]interface{}{
-								"env": []interface{}{
-									map[string]interface{}{
-										"name":  "KUBERNETES_CLUSTER_DOMAIN",
-										"value": "{{ quote .Values.kubernetesClusterDomain }}",
-									},
-								},
-								"image":     "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
-								"name":      "test-container",
-								"resources": map[string]interface{}{},
+								"helmifyDefault.env":          "test.testContainer.extraEnv",
+								"image":                       "{{ .Values.test.testContainer.image.repository }}:{{ .Values.test.testContainer.image.tag | default .Chart.AppVersion }}",
+								"name":                        "test-container",
+								"resources":                   map[string]interface{}{},
+								"helmifyDefault.envFrom":      "test.testContainer.extraEnvFrom",
+								"helmifyDefault.volumeMounts": "test.testContainer.extraVolumeMounts",
 							},
+							map[string]interface{}{"helmifyDefaultEntries": "test.extraContainers"},
 						},
+						"helmifyDefault.volumes":        "test.extraVolumes",
+						"helmifyDefault.initContainers": "test.extraInitContainers",
 					},
 				},
 				"updateStrategy": map[string]interface{}{},
 				"volumeClaimTemplates": []interface{}{
-					"{{- toYaml .Values.test.volumeClaimTemplates.data | nindent 8 }}",
-					"{{- toYaml .Values.test.volumeClaimTemplates.logs | nindent 8 }}",
+					"{{- tpl (toYaml .Values.test.volumeClaimTemplates.data) $ | nindent 8 }}",
+					"{{- tpl (toYaml .Values.test.volumeClaimTemplates.logs) $ | nindent 8 }}",
 				},
 			},
 			expectedPodValues: map[string]interface{}{
@@ -264,6 +276,9 @@ func TestProcessSpec_VolumeClaimTemplates(t *testing.T) {
 							"repository": "nginx",
 							"tag":        "1.14.2",
 						},
+						"extraEnv":          []interface{}{},
+						"extraEnvFrom":      []interface{}{},
+						"extraVolumeMounts": []interface{}{},
 					},
 					"volumeClaimTemplates": map[string]interface{}{
 						"data": map[string]interface{}{
@@ -294,6 +309,9 @@ func TestProcessSpec_VolumeClaimTemplates(t *testing.T) {
 							},
 						},
 					},
+					"extraVolumes":        []interface{}{},
+					"extraContainers":     []interface{}{},
+					"extraInitContainers": []interface{}{},
 				},
 			},
 		},
@@ -63,12 +63,16 @@ func (c cert) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructure
 		return true, nil, fmt.Errorf("%w: unable get cert dnsNames", err)
 	}
 
+	values := helmify.Values{}
 	processedDnsNames := []interface{}{}
 	for _, dnsName := range dnsNames {
 		dns := dnsName.(string)
 		templatedDns := appMeta.TemplatedString(dns)
 		processedDns := strings.ReplaceAll(templatedDns, appMeta.Namespace(), "{{ .Release.Namespace }}")
-		processedDns = strings.ReplaceAll(processedDns, cluster.DefaultDomain, fmt.Sprintf("{{ .Values.%s }}", cluster.DomainKey))
+		if strings.Contains(processedDns, cluster.DefaultDomain) {
+			processedDns = strings.ReplaceAll(processedDns, cluster.DefaultDomain, fmt.Sprintf("{{ .Values.%s }}", cluster.DomainKey))
+			values[cluster.DomainKey] = cluster.DefaultDomain
+		}
 		processedDnsNames = append(processedDnsNames, processedDns)
 	}
 	err = unstructured.SetNestedSlice(obj.Object, processedDnsNames, "spec", "dnsNames")
@@ -94,7 +98,6 @@ func (c cert) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructure
 	} else {
 		tmpl = certTempl
 	}
-	values := helmify.Values{}
 	if appMeta.Config().AddWebhookOption {
 		// Add webhook.enabled value to values.yaml
 		_, _ = values.Add(true, "webhook", "enabled")
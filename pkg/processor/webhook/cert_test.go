@@ -3,6 +3,7 @@ package webhook
 import (
 	"testing"
 
+	"github.com/EdgeGamingGG/helmify/pkg/config"
 	"github.com/EdgeGamingGG/helmify/pkg/metadata"
 
 	"github.com/EdgeGamingGG/helmify/internal"
@@ -32,6 +33,14 @@ func Test_cert_Process(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, true, processed)
 	})
+	t.Run("cluster domain value", func(t *testing.T) {
+		obj := internal.GenerateObj(certYaml)
+		testMeta := metadata.New(config.Config{ChartName: "my-operator"})
+		testMeta.Load(obj)
+		_, tmpl, err := testInstance.Process(testMeta, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, "cluster.local", tmpl.Values()["kubernetesClusterDomain"])
+	})
 	t.Run("skipped", func(t *testing.T) {
 		obj := internal.TestNs
 		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
@@ -0,0 +1,148 @@
+package refs
+
+import (
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// field - object field holding name of another object.
+type field struct {
+	// path - field path, see fieldpath.Parse.
+	path string
+	// kind - kind of the referenced object. Used if kindKey is not set or missing.
+	kind string
+	// kindKey - sibling field holding kind of the referenced object, e.g. kind of roleRef.
+	kindKey string
+}
+
+// nonObjectKinds - referenced kinds which are not k8s objects, e.g. RoleBinding subjects.
+var nonObjectKinds = map[string]bool{
+	"User":  true,
+	"Group": true,
+}
+
+// fields - known reference fields by qualified kind, see config.QualifiedKind.
+var fields = map[string][]field{
+	"Pod":             podSpec("spec"),
+	"apps/Deployment": podSpec("spec.template.spec"),
+	"apps/DaemonSet":  podSpec("spec.template.spec"),
+	"apps/ReplicaSet": podSpec("spec.template.spec"),
+	"batch/Job":       podSpec("spec.template.spec"),
+	"batch/CronJob":   podSpec("spec.jobTemplate.spec.template.spec"),
+	"apps/StatefulSet": append(podSpec("spec.template.spec"),
+		field{path: "spec.serviceName", kind: "Service"},
+		field{path: "spec.volumeClaimTemplates[*].spec.storageClassName", kind: "StorageClass"},
+		field{path: "spec.volumeClaimTemplates[*].spec.volumeName", kind: "PersistentVolume"},
+	),
+	"argoproj.io/Rollout": append(podSpec("spec.template.spec"),
+		field{path: "spec.workloadRef.name", kindKey: "kind"},
+		field{path: "spec.strategy.canary.canaryService", kind: "Service"},
+		field{path: "spec.strategy.canary.stableService", kind: "Service"},
+		field{path: "spec.strategy.canary.trafficRouting.nginx.stableIngress", kind: "Ingress"},
+		field{path: "spec.strategy.blueGreen.activeService", kind: "Service"},
+		field{path: "spec.strategy.blueGreen.previewService", kind: "Service"},
+	),
+	"PersistentVolumeClaim": {
+		{path: "spec.storageClassName", kind: "StorageClass"},
+		{path: "spec.volumeName", kind: "PersistentVolume"},
+	},
+	"ServiceAccount": {
+		{path: "secrets[*].name", kind: "Secret"},
+		{path: "imagePullSecrets[*].name", kind: "Secret"},
+	},
+	"networking.k8s.io/Ingress": {
+		{path: "spec.ingressClassName", kind: "IngressClass"},
+		{path: "spec.tls[*].secretName", kind: "Secret"},
+		{path: "spec.defaultBackend.service.name", kind: "Service"},
+		{path: "spec.rules[*].http.paths[*].backend.service.name", kind: "Service"},
+	},
+	"autoscaling/HorizontalPodAutoscaler": {
+		{path: "spec.scaleTargetRef.name", kindKey: "kind"},
+	},
+	"rbac.authorization.k8s.io/RoleBinding": {
+		{path: "roleRef.name", kindKey: "kind"},
+		{path: "subjects[*].name", kindKey: "kind"},
+	},
+	"rbac.authorization.k8s.io/ClusterRoleBinding": {
+		{path: "roleRef.name", kindKey: "kind"},
+		{path: "subjects[*].name", kindKey: "kind"},
+	},
+	"admissionregistration.k8s.io/ValidatingWebhookConfiguration": {
+		{path: "webhooks[*].clientConfig.service.name", kind: "Service"},
+	},
+	"admissionregistration.k8s.io/MutatingWebhookConfiguration": {
+		{path: "webhooks[*].clientConfig.service.name", kind: "Service"},
+	},
+	"apiextensions.k8s.io/CustomResourceDefinition": {
+		{path: "spec.conversion.webhook.clientConfig.service.name", kind: "Service"},
+	},
+	"apiregistration.k8s.io/APIService": {
+		{path: "spec.service.name", kind: "Service"},
+	},
+	"cert-manager.io/Certificate": {
+		{path: "spec.secretName", kind: "Secret"},
+		{path: "spec.issuerRef.name", kind: "Issuer", kindKey: "kind"},
+	},
+	"gateway.networking.k8s.io/Gateway": {
+		{path: "spec.listeners[*].tls.certificateRefs[*].name", kind: "Secret", kindKey: "kind"},
+	},
+	"gateway.networking.k8s.io/HTTPRoute": routeFields,
+	"gateway.networking.k8s.io/GRPCRoute": routeFields,
+	"keda.sh/ScaledObject": {
+		{path: "spec.scaleTargetRef.name", kind: "Deployment", kindKey: "kind"},
+	},
+}
+
+var routeFields = []field{
+	{path: "spec.parentRefs[*].name", kind: "Gateway", kindKey: "kind"},
+	{path: "spec.rules[*].backendRefs[*].name", kind: "Service", kindKey: "kind"},
+}
+
+// podSpec returns reference fields of the pod spec with given path.
+func podSpec(path string) []field {
+	res := []field{
+		{path: path + ".serviceAccountName", kind: "ServiceAccount"},
+		{path: path + ".priorityClassName", kind: "PriorityClass"},
+		{path: path + ".runtimeClassName", kind: "RuntimeClass"},
+		{path: path + ".imagePullSecrets[*].name", kind: "Secret"},
+		{path: path + ".volumes[*].secret.secretName", kind: "Secret"},
+		{path: path + ".volumes[*].configMap.name", kind: "ConfigMap"},
+		{path: path + ".volumes[*].persistentVolumeClaim.claimName", kind: "PersistentVolumeClaim"},
+		{path: path + ".volumes[*].projected.sources[*].secret.name", kind: "Secret"},
+		{path: path + ".volumes[*].projected.sources[*].configMap.name", kind: "ConfigMap"},
+	}
+	for _, containers := range []string{"containers", "initContainers"} {
+		c := path + "." + containers + "[*]"
+		res = append(res,
+			field{path: c + ".env[*].valueFrom.secretKeyRef.name", kind: "Secret"},
+			field{path: c + ".env[*].valueFrom.configMapKeyRef.name", kind: "ConfigMap"},
+			field{path: c + ".envFrom[*].secretRef.name", kind: "Secret"},
+			field{path: c + ".envFrom[*].configMapRef.name", kind: "ConfigMap"},
+		)
+	}
+	return res
+}
+
+// fieldsOf returns known reference fields of the object and reference fields from config.
+// Pod specs of objects unknown to helmify are looked up the same way as by the default processor,
+// see config.ObjectOptions.PodSpecs.
+func fieldsOf(conf config.Config, obj *unstructured.Unstructured) []field {
+	gvk := obj.GroupVersionKind()
+	kind := config.QualifiedKind(gvk.Group, gvk.Kind)
+	known, ok := fields[kind]
+	res := append([]field(nil), known...)
+	opts := conf.ObjectOptions(kind, obj.GetName())
+	if !ok {
+		podSpecs := opts.PodSpecs
+		if podSpecs == nil {
+			podSpecs = []string{"spec.template.spec"}
+		}
+		for _, p := range podSpecs {
+			res = append(res, podSpec(p)...)
+		}
+	}
+	for _, r := range opts.References {
+		res = append(res, field{path: r.Path, kind: r.Kind})
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+// Package refs tracks references between k8s objects by name. References to objects presented in the chart
+// are rewritten to templated names, so renamed objects are referenced consistently.
+package refs
+
+import (
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/fieldpath"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Ref - reference from the object field to another object.
+type Ref struct {
+	// Kind and Name of the referencing object.
+	Kind, Name string
+	// Path of the referencing field, e.g. spec.template.spec.volumes[*].secret.secretName.
+	Path string
+	// RefKind and RefName of the referenced object.
+	RefKind, RefName string
+}
+
+// Graph - names of chart objects and references between them.
+type Graph struct {
+	objects map[string]struct{}
+	refs    []Ref
+}
+
+// New returns empty reference graph.
+func New() *Graph {
+	return &Graph{objects: map[string]struct{}{}}
+}
+
+// Load records the object and its references. All objects must be loaded before Rewrite.
+func (g *Graph) Load(conf config.Config, obj *unstructured.Unstructured) {
+	g.objects[key(obj.GetKind(), obj.GetName())] = struct{}{}
+	walk(conf, obj.Object, obj, func(f field, parent map[string]interface{}, key, kind, name string) {
+		g.refs = append(g.refs, Ref{Kind: obj.GetKind(), Name: obj.GetName(), Path: f.path, RefKind: kind, RefName: name})
+	})
+}
+
+// Rewrite returns a copy of the object with references to chart objects replaced by templated names.
+// References to objects missing in the chart are kept as is, see Dangling.
+func (g *Graph) Rewrite(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	res := obj.DeepCopy()
+	walk(appMeta.Config(), res.Object, obj, func(_ field, parent map[string]interface{}, k, kind, name string) {
+		if _, ok := g.objects[key(kind, name)]; ok {
+			parent[k] = appMeta.TemplatedName(name)
+		}
+	})
+	return res
+}
+
+// Dangling returns references to objects missing in the chart in order of loading.
+func (g *Graph) Dangling() []Ref {
+	var res []Ref
+	for _, r := range g.refs {
+		if _, ok := g.objects[key(r.RefKind, r.RefName)]; !ok {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+func key(kind, name string) string {
+	return kind + "/" + name
+}
+
+// walk calls fn for every reference field of the object content. obj is the original object used to look up fields.
+func walk(conf config.Config, content map[string]interface{}, obj *unstructured.Unstructured, fn func(f field, parent map[string]interface{}, key, kind, name string)) {
+	for _, f := range fieldsOf(conf, obj) {
+		p, err := fieldpath.Parse(f.path)
+		if err != nil {
+			// custom reference paths are checked by config validation
+			continue
+		}
+		p.Walk(content, func(parent map[string]interface{}, key string) {
+			name, _ := parent[key].(string)
+			if name == "" {
+				return
+			}
+			kind := f.kind
+			if f.kindKey != "" {
+				if k, ok := parent[f.kindKey].(string); ok && k != "" {
+					kind = k
+				}
+			}
+			if kind == "" || nonObjectKinds[kind] {
+				return
+			}
+			fn(f, parent, key, kind, name)
+		})
+	}
+}
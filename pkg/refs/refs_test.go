@@ -0,0 +1,172 @@
+package refs
+
+import (
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	deploymentYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-web
+spec:
+  template:
+    spec:
+      serviceAccountName: my-app-sa
+      priorityClassName: my-app-critical
+      volumes:
+      - name: certs
+        secret:
+          secretName: my-app-certs
+      - name: config
+        configMap:
+          name: my-app-certs
+      containers:
+      - name: web
+        envFrom:
+        - secretRef:
+            name: external-secret`
+	statefulSetYaml = `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-app-db
+spec:
+  serviceName: my-app-db
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      storageClassName: my-app-db`
+	ingressYaml = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: my-app-ingress
+spec:
+  tls:
+  - secretName: my-app-certs
+  rules:
+  - http:
+      paths:
+      - path: /
+        backend:
+          service:
+            name: my-app-db`
+	roleBindingYaml = `apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: my-app-binding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: my-app-role
+subjects:
+- kind: ServiceAccount
+  name: my-app-sa
+- kind: User
+  name: admin`
+	customYaml = `apiVersion: example.com/v1
+kind: Backup
+metadata:
+  name: my-app-backup
+spec:
+  targets:
+  - credentials: my-app-certs
+  - credentials: other`
+)
+
+func object(kind, name string) string {
+	return "apiVersion: v1\nkind: " + kind + "\nmetadata:\n  name: " + name
+}
+
+func load(conf config.Config, objs ...string) (*Graph, *metadata.Service) {
+	conf.ChartName = "chart"
+	g := New()
+	meta := metadata.New(conf)
+	for _, o := range objs {
+		obj := internal.GenerateObj(o)
+		g.Load(conf, obj)
+		meta.Load(obj)
+	}
+	return g, meta
+}
+
+func get(t *testing.T, obj *unstructured.Unstructured, fields ...interface{}) interface{} {
+	var cur interface{} = obj.Object
+	for _, f := range fields {
+		switch k := f.(type) {
+		case string:
+			cur = cur.(map[string]interface{})[k]
+		case int:
+			cur = cur.([]interface{})[k]
+		}
+		require.NotNil(t, cur, fields)
+	}
+	return cur
+}
+
+func TestGraph_Rewrite(t *testing.T) {
+	objs := []string{deploymentYaml, statefulSetYaml, ingressYaml, roleBindingYaml,
+		object("ServiceAccount", "my-app-sa"), object("Secret", "my-app-certs"), object("Service", "my-app-db")}
+	g, meta := load(config.Config{}, objs...)
+
+	t.Run("pod spec", func(t *testing.T) {
+		obj := internal.GenerateObj(deploymentYaml)
+		res := g.Rewrite(meta, obj)
+		spec := []interface{}{"spec", "template", "spec"}
+		assert.Equal(t, `{{ include "chart.fullname" . }}-sa`, get(t, res, append(spec, "serviceAccountName")...))
+		assert.Equal(t, `{{ include "chart.fullname" . }}-certs`, get(t, res, append(spec, "volumes", 0, "secret", "secretName")...))
+		assert.Equal(t, "my-app-certs", get(t, res, append(spec, "volumes", 1, "configMap", "name")...), "must match kind")
+		assert.Equal(t, "my-app-critical", get(t, res, append(spec, "priorityClassName")...))
+		assert.Equal(t, "my-app-sa", get(t, obj, append(spec, "serviceAccountName")...), "original must not be changed")
+	})
+	t.Run("storage class", func(t *testing.T) {
+		res := g.Rewrite(meta, internal.GenerateObj(statefulSetYaml))
+		assert.Equal(t, `{{ include "chart.fullname" . }}-db`, get(t, res, "spec", "serviceName"))
+		assert.Equal(t, "my-app-db", get(t, res, "spec", "volumeClaimTemplates", 0, "spec", "storageClassName"))
+	})
+	t.Run("ingress", func(t *testing.T) {
+		res := g.Rewrite(meta, internal.GenerateObj(ingressYaml))
+		assert.Equal(t, `{{ include "chart.fullname" . }}-certs`, get(t, res, "spec", "tls", 0, "secretName"))
+		assert.Equal(t, `{{ include "chart.fullname" . }}-db`, get(t, res, "spec", "rules", 0, "http", "paths", 0, "backend", "service", "name"))
+	})
+	t.Run("kind from reference", func(t *testing.T) {
+		res := g.Rewrite(meta, internal.GenerateObj(roleBindingYaml))
+		assert.Equal(t, "my-app-role", get(t, res, "roleRef", "name"))
+		assert.Equal(t, `{{ include "chart.fullname" . }}-sa`, get(t, res, "subjects", 0, "name"))
+		assert.Equal(t, "admin", get(t, res, "subjects", 1, "name"))
+	})
+	t.Run("original names", func(t *testing.T) {
+		g, meta := load(config.Config{OriginalName: true}, objs...)
+		res := g.Rewrite(meta, internal.GenerateObj(ingressYaml))
+		assert.Equal(t, "my-app-certs", get(t, res, "spec", "tls", 0, "secretName"))
+	})
+}
+
+func TestGraph_Rewrite_custom(t *testing.T) {
+	conf := config.Config{Kinds: map[string]config.ObjectOptions{
+		"example.com/Backup": {References: []config.Reference{{Path: "spec.targets[*].credentials", Kind: "Secret"}}},
+	}}
+	g, meta := load(conf, customYaml, object("Secret", "my-app-certs"))
+	res := g.Rewrite(meta, internal.GenerateObj(customYaml))
+	assert.Equal(t, `{{ include "chart.fullname" . }}-certs`, get(t, res, "spec", "targets", 0, "credentials"))
+	assert.Equal(t, "other", get(t, res, "spec", "targets", 1, "credentials"))
+	assert.Equal(t, []Ref{
+		{Kind: "Backup", Name: "my-app-backup", Path: "spec.targets[*].credentials", RefKind: "Secret", RefName: "other"},
+	}, g.Dangling())
+}
+
+func TestGraph_Dangling(t *testing.T) {
+	g, _ := load(config.Config{}, deploymentYaml, roleBindingYaml, object("ServiceAccount", "my-app-sa"), object("Secret", "my-app-certs"))
+	var dangling []string
+	for _, r := range g.Dangling() {
+		dangling = append(dangling, r.RefKind+"/"+r.RefName)
+	}
+	assert.Equal(t, []string{"PriorityClass/my-app-critical", "ConfigMap/my-app-certs", "Secret/external-secret", "Role/my-app-role"}, dangling)
+}
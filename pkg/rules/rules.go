@@ -0,0 +1,182 @@
+// Package rules applies user defined rules on top of templates produced by processors.
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/fieldpath"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Apply lifts object fields selected by rules into values. Field value is taken from the original object
+// and the field in the rendered template is replaced with a reference to values. Values generated by processor
+// for the replaced field are removed unless referenced elsewhere in the template.
+// Rules for fields missing in the object are ignored.
+func Apply(appMeta helmify.AppMetadata, obj *unstructured.Unstructured, template helmify.Template, rules []config.ValueRule) (helmify.Template, error) {
+	if len(rules) == 0 || template == nil {
+		return template, nil
+	}
+	buf := bytes.Buffer{}
+	err := template.Write(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to write template", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	values := helmify.Values{}
+	var superseded []string
+	for _, rule := range rules {
+		log := logrus.WithFields(logrus.Fields{"Kind": obj.GetKind(), "Name": obj.GetName(), "Path": rule.Path})
+		path, err := fieldpath.Parse(rule.Path)
+		if err != nil {
+			return nil, err
+		}
+		value, found := path.Get(obj.Object)
+		if !found {
+			log.Debug("rule skipped: field not found")
+			continue
+		}
+		valuePath := rule.Value
+		if valuePath == "" {
+			valuePath = defaultValuePath(appMeta, obj, path)
+		}
+		node := parseTemplate(lines).find(path)
+		if node == nil {
+			log.Warn("rule skipped: field is already templated or not found in template")
+			continue
+		}
+		name := strings.Split(valuePath, ".")
+		err = unstructured.SetNestedField(values, runtime.DeepCopyJSONValue(value), name...)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to set value %s", err, valuePath)
+		}
+		superseded = append(superseded, valueRefs(lines[node.start:node.end+1])...)
+		lines = node.replace(lines, valueTemplate(value, valuePath, node.col))
+		log.WithField("Value", valuePath).Debug("field lifted into values")
+	}
+	content := strings.Join(lines, "\n")
+	res := &result{template: template, content: []byte(content), values: template.Values()}
+	if res.values == nil {
+		res.values = helmify.Values{}
+	}
+	// values of the replaced templates are not used anymore
+	for _, ref := range superseded {
+		if !isReferenced(content, ref) {
+			removeValue(res.values, strings.Split(ref, "."))
+		}
+	}
+	// rule values override values generated by processor
+	for key, value := range values {
+		res.values[key] = mergeOverride(res.values[key], value)
+	}
+	return res, nil
+}
+
+// defaultValuePath returns object name followed by values of list selectors and the field name.
+// Example: foo.app.livenessProbe for Deployment foo and path spec.template.spec.containers[name=app].livenessProbe.
+func defaultValuePath(appMeta helmify.AppMetadata, obj *unstructured.Unstructured, path fieldpath.Path) string {
+	name := []string{strcase.ToLowerCamel(appMeta.TrimName(obj.GetName()))}
+	for _, seg := range path[:len(path)-1] {
+		switch {
+		case seg.Field != "":
+			name = append(name, strcase.ToLowerCamel(seg.FieldValue))
+		case seg.Index >= 0:
+			name = append(name, fmt.Sprintf("%s%d", strcase.ToLowerCamel(seg.Key), seg.Index))
+		}
+	}
+	last := path[len(path)-1]
+	name = append(name, strcase.ToLowerCamel(last.Key))
+	if last.Field != "" {
+		name = append(name, strcase.ToLowerCamel(last.FieldValue))
+	} else if last.Index >= 0 {
+		name[len(name)-1] = fmt.Sprintf("%s%d", name[len(name)-1], last.Index)
+	}
+	return strings.Join(name, ".")
+}
+
+func valueTemplate(value interface{}, valuePath string, col int) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return fmt.Sprintf("{{- toYaml .Values.%s | nindent %d }}", valuePath, col+2)
+	case string:
+		return fmt.Sprintf("{{ .Values.%s | quote }}", valuePath)
+	default:
+		return fmt.Sprintf("{{ .Values.%s }}", valuePath)
+	}
+}
+
+var valueRefRegexp = regexp.MustCompile(`\.Values((?:\.\w+)+)`)
+
+// valueRefs returns paths of the values referenced by the template lines.
+func valueRefs(lines []string) []string {
+	var res []string
+	for _, m := range valueRefRegexp.FindAllStringSubmatch(strings.Join(lines, "\n"), -1) {
+		res = append(res, strings.TrimPrefix(m[1], "."))
+	}
+	return res
+}
+
+// isReferenced returns true if the value or any of its nested values is referenced by the template.
+func isReferenced(content, valuePath string) bool {
+	return regexp.MustCompile(regexp.QuoteMeta(".Values."+valuePath) + `\b`).MatchString(content)
+}
+
+// removeValue removes the value and its parent maps left empty.
+func removeValue(values helmify.Values, name []string) {
+	unstructured.RemoveNestedField(values, name...)
+	for i := len(name) - 1; i > 0; i-- {
+		parent, found, _ := unstructured.NestedMap(values, name[:i]...)
+		if !found || len(parent) != 0 {
+			return
+		}
+		unstructured.RemoveNestedField(values, name[:i]...)
+	}
+}
+
+// mergeOverride merges src into dst maps recursively. Values from src win.
+func mergeOverride(dst, src interface{}) interface{} {
+	dstMap, ok := dst.(map[string]interface{})
+	srcMap, ok2 := src.(map[string]interface{})
+	if !ok || !ok2 {
+		return src
+	}
+	for key, value := range srcMap {
+		dstMap[key] = mergeOverride(dstMap[key], value)
+	}
+	return dstMap
+}
+
+type result struct {
+	template helmify.Template
+	content  []byte
+	values   helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.template.Filename()
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write(r.content)
+	return err
+}
+
+// Schema returns schema keywords of the original template.
+func (r *result) Schema() helmify.Schema {
+	if provider, ok := r.template.(helmify.SchemaProvider); ok {
+		return provider.Schema()
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package rules
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/internal"
+	"github.com/EdgeGamingGG/helmify/pkg/config"
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/EdgeGamingGG/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deploymentYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-operator-foo
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: proxy
+        image: proxy:1.0
+      - name: app
+        image: app:1.0
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8081
+        args:
+        - --verbose
+      terminationGracePeriodSeconds: 10`
+
+const renderedYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "chart.fullname" . }}-foo
+  labels:
+  {{- include "chart.labels" . | nindent 4 }}
+spec:
+  replicas: {{ .Values.foo.replicas }}
+  template:
+    spec:
+      containers:
+      - image: {{ .Values.foo.proxy.image }}
+        name: proxy
+      - args: {{- toYaml .Values.foo.app.args | nindent 8 }}
+        image: {{ .Values.foo.app.image }}
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8081
+        name: app
+      terminationGracePeriodSeconds: 10`
+
+type testTemplate struct {
+	data   string
+	values helmify.Values
+}
+
+func (t testTemplate) Filename() string {
+	return "foo.yaml"
+}
+
+func (t testTemplate) Values() helmify.Values {
+	return t.values
+}
+
+func (t testTemplate) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte(t.data))
+	return err
+}
+
+func TestApply(t *testing.T) {
+	obj := internal.GenerateObj(deploymentYaml)
+	appMeta := metadata.New(config.Config{ChartName: "chart"})
+	appMeta.Load(obj)
+	appMeta.Load(internal.GenerateObj("kind: Service\nmetadata:\n  name: my-operator-bar"))
+	tpl := testTemplate{data: renderedYaml, values: helmify.Values{"foo": map[string]interface{}{"replicas": int64(1)}}}
+
+	res, err := Apply(appMeta, obj, tpl, []config.ValueRule{
+		{Path: "spec.template.spec.containers[name=app].livenessProbe"},
+		{Path: "spec.template.spec.terminationGracePeriodSeconds", Value: "foo.grace"},
+		{Path: "spec.template.spec.containers[0]"},
+		{Path: "spec.template.spec.containers[name=app].args[0]"},
+		{Path: "spec.template.spec.containers[name=app].readinessProbe"},
+	})
+	require.NoError(t, err)
+	buf := bytes.Buffer{}
+	require.NoError(t, res.Write(&buf))
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "chart.fullname" . }}-foo
+  labels:
+  {{- include "chart.labels" . | nindent 4 }}
+spec:
+  replicas: {{ .Values.foo.replicas }}
+  template:
+    spec:
+      containers:
+      - {{- toYaml .Values.foo.containers0 | nindent 8 }}
+      - args: {{- toYaml .Values.foo.app.args | nindent 8 }}
+        image: {{ .Values.foo.app.image }}
+        livenessProbe: {{- toYaml .Values.foo.app.livenessProbe | nindent 10 }}
+        name: app
+      terminationGracePeriodSeconds: {{ .Values.foo.grace }}`, buf.String())
+	assert.Equal(t, helmify.Values{"foo": map[string]interface{}{
+		"replicas": int64(1),
+		"grace":    int64(10),
+		"app": map[string]interface{}{
+			"livenessProbe": map[string]interface{}{"httpGet": map[string]interface{}{"path": "/healthz", "port": int64(8081)}},
+		},
+		"containers0": map[string]interface{}{"name": "proxy", "image": "proxy:1.0"},
+	}}, res.Values())
+	assert.Equal(t, "foo.yaml", res.Filename())
+
+	t.Run("templated field", func(t *testing.T) {
+		tpl := testTemplate{data: renderedYaml, values: helmify.Values{"foo": map[string]interface{}{
+			"replicas": int64(1),
+			"proxy":    map[string]interface{}{"image": "proxy:1.0"},
+			"app": map[string]interface{}{
+				"image": "app:1.0",
+				"args":  []interface{}{"--verbose"},
+			},
+		}}}
+		res, err := Apply(appMeta, obj, tpl, []config.ValueRule{
+			{Path: "spec.template.spec.containers[name=app].image", Value: "images.app"},
+			{Path: "spec.template.spec.containers[name=proxy].image"},
+		})
+		require.NoError(t, err)
+		buf := bytes.Buffer{}
+		require.NoError(t, res.Write(&buf))
+		assert.Contains(t, buf.String(), `      - image: {{ .Values.foo.proxy.image | quote }}
+        name: proxy
+      - args: {{- toYaml .Values.foo.app.args | nindent 8 }}
+        image: {{ .Values.images.app | quote }}`)
+		assert.Equal(t, helmify.Values{
+			"foo": map[string]interface{}{
+				"replicas": int64(1),
+				"proxy":    map[string]interface{}{"image": "proxy:1.0"},
+				"app":      map[string]interface{}{"args": []interface{}{"--verbose"}},
+			},
+			"images": map[string]interface{}{"app": "app:1.0"},
+		}, res.Values(), "superseded foo.app.image value must be removed")
+	})
+	t.Run("no rules", func(t *testing.T) {
+		res, err := Apply(appMeta, obj, tpl, nil)
+		require.NoError(t, err)
+		assert.Equal(t, tpl, res)
+	})
+	t.Run("invalid path", func(t *testing.T) {
+		_, err := Apply(appMeta, obj, tpl, []config.ValueRule{{Path: "spec["}})
+		assert.Error(t, err)
+	})
+}
+
+func Test_parseTemplate(t *testing.T) {
+	lines := []string{
+		"data:",
+		"  script: |",
+		"    key: value",
+		"",
+		"    other: value",
+		"  \"quoted.key\": x",
+		"items:",
+		"  - a",
+		"  - name: b",
+		"    value: c",
+	}
+	root := parseTemplate(lines)
+	script := root.child("data").child("script")
+	require.NotNil(t, script)
+	assert.Equal(t, 1, script.start)
+	assert.Equal(t, 4, script.end)
+	assert.Empty(t, script.children)
+	assert.NotNil(t, root.child("data").child("quoted.key"))
+	items := root.child("items")
+	require.Len(t, items.children, 2)
+	assert.Equal(t, "a", items.children[0].value)
+	assert.Equal(t, 9, items.children[1].end)
+}
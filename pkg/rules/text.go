@@ -0,0 +1,140 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/EdgeGamingGG/helmify/pkg/fieldpath"
+)
+
+var keyLine = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s"'{#-][^:]*?):(?:\s+(.*))?$`)
+
+// node - yaml mapping key or list item in a rendered template.
+// Templates are not valid yaml, so structure is restored from indentation, skipping template only lines.
+type node struct {
+	key  string
+	item bool
+	// value - inline value of the key or scalar list item
+	value string
+	// col - column of the key or of the list item dash
+	col        int
+	start, end int
+	children   []*node
+}
+
+// parseTemplate returns root node of the rendered template lines.
+func parseTemplate(lines []string) *node {
+	root := &node{col: -1, start: -1}
+	stack := []*node{root}
+	var block *node
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		col := len(line) - len(trimmed)
+		if block != nil && (trimmed == "" || col > block.col) {
+			// content of block scalar
+			for _, n := range stack {
+				n.end = i
+			}
+			continue
+		}
+		block = nil
+		if trimmed == "" || strings.HasPrefix(trimmed, "{{") || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		var nodes []*node
+		if trimmed == "-" || strings.HasPrefix(trimmed, "- ") {
+			for len(stack) > 1 && (stack[len(stack)-1].col > col || stack[len(stack)-1].col == col && stack[len(stack)-1].item) {
+				stack = stack[:len(stack)-1]
+			}
+			nodes = append(nodes, &node{item: true, col: col, start: i})
+			trimmed = strings.TrimPrefix(strings.TrimPrefix(trimmed, "-"), " ")
+			col += 2
+		} else {
+			for len(stack) > 1 && stack[len(stack)-1].col >= col {
+				stack = stack[:len(stack)-1]
+			}
+		}
+		if match := keyLine.FindStringSubmatch(trimmed); match != nil {
+			key := &node{key: strings.Trim(match[1], `"'`), value: match[2], col: col, start: i}
+			nodes = append(nodes, key)
+			if strings.HasPrefix(key.value, "|") || strings.HasPrefix(key.value, ">") {
+				block = key
+			}
+		} else if len(nodes) != 0 {
+			nodes[0].value = trimmed
+		}
+		for _, n := range nodes {
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, n)
+			stack = append(stack, n)
+		}
+		for _, n := range stack {
+			n.end = i
+		}
+	}
+	return root
+}
+
+// find returns node with the given path or nil if not found.
+func (n *node) find(path fieldpath.Path) *node {
+	cur := n
+	for _, seg := range path {
+		cur = cur.child(seg.Key)
+		if cur == nil {
+			return nil
+		}
+		if seg.Selector() {
+			cur = cur.listItem(seg)
+			if cur == nil {
+				return nil
+			}
+		}
+	}
+	return cur
+}
+
+// child returns key node.
+func (n *node) child(key string) *node {
+	for _, c := range n.children {
+		if c.key == key && !c.item {
+			return c
+		}
+	}
+	return nil
+}
+
+// listItem returns list item of the key node selected by the segment.
+func (n *node) listItem(seg fieldpath.Segment) *node {
+	var items []*node
+	for _, c := range n.children {
+		if c.item {
+			items = append(items, c)
+		}
+	}
+	if seg.Index >= 0 {
+		if seg.Index < len(items) {
+			return items[seg.Index]
+		}
+		return nil
+	}
+	for _, item := range items {
+		field := item.child(seg.Field)
+		if field != nil && strings.Trim(field.value, `"'`) == seg.FieldValue {
+			return item
+		}
+	}
+	return nil
+}
+
+// replace replaces node lines with the node key and given value.
+func (n *node) replace(lines []string, value string) []string {
+	first := lines[n.start]
+	line := first[:n.col] + "- " + value
+	if !n.item {
+		rest := first[n.col:]
+		line = first[:n.col] + rest[:strings.Index(rest, ":")] + ": " + value
+	}
+	res := append([]string{}, lines[:n.start]...)
+	res = append(res, line)
+	return append(res, lines[n.end+1:]...)
+}
@@ -0,0 +1,70 @@
+// Package toggle wraps templates produced by processors into enabled conditional.
+package toggle
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
+)
+
+const (
+	header = "{{- if .Values.%s.enabled }}\n"
+	footer = "\n{{- end }}"
+)
+
+// Wrap wraps template into {{- if .Values.<name><Kind>.enabled }} conditional. Name followed by kind is converted
+// to camel case, so objects of different kinds with the same name and values of the object itself don't collide.
+// Value defaults to true, so template is rendered unless disabled by chart user.
+func Wrap(template helmify.Template, kind, name string) (helmify.Template, error) {
+	if template == nil {
+		return nil, nil
+	}
+	values := helmify.Values{}
+	err := values.Merge(template.Values())
+	if err != nil {
+		return nil, err
+	}
+	name = strcase.ToLowerCamel(name + "-" + kind)
+	_, err = values.Add(true, name, "enabled")
+	if err != nil {
+		return nil, err
+	}
+	return &result{template: template, name: name, values: values}, nil
+}
+
+type result struct {
+	template helmify.Template
+	name     string
+	values   helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.template.Filename()
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := fmt.Fprintf(writer, header, r.name)
+	if err != nil {
+		return err
+	}
+	err = r.template.Write(writer)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(writer, footer)
+	return err
+}
+
+// Schema returns schema keywords of the original template.
+func (r *result) Schema() helmify.Schema {
+	if provider, ok := r.template.(helmify.SchemaProvider); ok {
+		return provider.Schema()
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package toggle
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/EdgeGamingGG/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTemplate struct {
+	values helmify.Values
+}
+
+func (t testTemplate) Filename() string { return "metrics.yaml" }
+
+func (t testTemplate) Values() helmify.Values { return t.values }
+
+func (t testTemplate) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte("kind: Service\nspec:\n  type: {{ .Values.metrics.type }}"))
+	return err
+}
+
+func (t testTemplate) Schema() helmify.Schema {
+	return helmify.Schema{"metrics": map[string]interface{}{"minimum": 0}}
+}
+
+func TestWrap(t *testing.T) {
+	tmpl, err := Wrap(testTemplate{values: helmify.Values{"metrics": map[string]interface{}{"type": "ClusterIP"}}}, "Service", "metrics")
+	require.NoError(t, err)
+	assert.Equal(t, "metrics.yaml", tmpl.Filename())
+	assert.Equal(t, helmify.Values{
+		"metrics":        map[string]interface{}{"type": "ClusterIP"},
+		"metricsService": map[string]interface{}{"enabled": true},
+	}, tmpl.Values())
+
+	buf := bytes.Buffer{}
+	require.NoError(t, tmpl.Write(&buf))
+	assert.Equal(t, "{{- if .Values.metricsService.enabled }}\nkind: Service\nspec:\n  type: {{ .Values.metrics.type }}\n{{- end }}", buf.String())
+	assert.NotNil(t, tmpl.(helmify.SchemaProvider).Schema())
+
+	t.Run("same name of different kinds", func(t *testing.T) {
+		deploy, err := Wrap(testTemplate{values: helmify.Values{"metrics": map[string]interface{}{"replicas": 1}}}, "Deployment", "metrics")
+		require.NoError(t, err)
+		values := helmify.Values{}
+		require.NoError(t, values.Merge(tmpl.Values()))
+		require.NoError(t, values.Merge(deploy.Values()))
+		assert.Equal(t, map[string]interface{}{"enabled": true}, values["metricsService"])
+		assert.Equal(t, map[string]interface{}{"enabled": true}, values["metricsDeployment"])
+		assert.NotContains(t, values["metrics"], "enabled", "toggle must not be added to object values")
+	})
+	t.Run("nil template", func(t *testing.T) {
+		tmpl, err := Wrap(nil, "Service", "metrics")
+		assert.NoError(t, err)
+		assert.Nil(t, tmpl)
+	})
+	t.Run("value conflict", func(t *testing.T) {
+		_, err := Wrap(testTemplate{values: helmify.Values{"metricsService": "ClusterIP"}}, "Service", "metrics")
+		assert.Error(t, err)
+	})
+}
@@ -2,6 +2,8 @@ package yaml
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
 
 	"sigs.k8s.io/yaml"
 )
@@ -26,3 +28,48 @@ func Marshal(object interface{}, indent int) (string, error) {
 	objectBytes = bytes.TrimRight(objectBytes, "\n ")
 	return string(objectBytes), nil
 }
+
+// templateMarker - plain scalar put instead of a template string while marshalling, see MarshalTemplated.
+const templateMarker = "helmifyTemplate.%d"
+
+// MarshalTemplated marshals object like Marshal, but keeps string values with helm template expressions unquoted.
+// Such values are replaced with markers before marshalling and restored afterwards, other strings are kept quoted.
+func MarshalTemplated(object interface{}, indent int) (string, error) {
+	var templates []string
+	object = replaceTemplates(object, &templates)
+	res, err := Marshal(object, indent)
+	if err != nil {
+		return "", err
+	}
+	// restore in reverse order, so helmifyTemplate.1 does not match helmifyTemplate.10
+	for i := len(templates) - 1; i >= 0; i-- {
+		res = strings.Replace(res, fmt.Sprintf(templateMarker, i), templates[i], 1)
+	}
+	return res, nil
+}
+
+// replaceTemplates returns a copy of the object with single line template strings replaced by markers.
+func replaceTemplates(object interface{}, templates *[]string) interface{} {
+	switch v := object.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			res[k] = replaceTemplates(val, templates)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, val := range v {
+			res[i] = replaceTemplates(val, templates)
+		}
+		return res
+	case string:
+		if !strings.Contains(v, "{{") || strings.Contains(v, "\n") {
+			return v
+		}
+		*templates = append(*templates, v)
+		return fmt.Sprintf(templateMarker, len(*templates)-1)
+	default:
+		return object
+	}
+}
@@ -44,3 +44,40 @@ func TestIndent(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalTemplated(t *testing.T) {
+	tests := []struct {
+		name   string
+		object interface{}
+		want   string
+	}{
+		{
+			name:   "template",
+			object: map[string]interface{}{"name": `{{ include "app.fullname" . }}-svc`},
+			want:   `name: {{ include "app.fullname" . }}-svc`,
+		},
+		{
+			name:   "quoted strings",
+			object: map[string]interface{}{"a": "*", "b": "it's ok", "c": "80"},
+			want:   "a: '*'\nb: it's ok\nc: \"80\"",
+		},
+		{
+			name: "many templates",
+			object: []interface{}{"{{ 0 }}", "{{ 1 }}", "{{ 2 }}", "{{ 3 }}", "{{ 4 }}", "{{ 5 }}",
+				"{{ 6 }}", "{{ 7 }}", "{{ 8 }}", "{{ 9 }}", "{{ 10 }}", "{{ 11 }}"},
+			want: "- {{ 0 }}\n- {{ 1 }}\n- {{ 2 }}\n- {{ 3 }}\n- {{ 4 }}\n- {{ 5 }}\n" +
+				"- {{ 6 }}\n- {{ 7 }}\n- {{ 8 }}\n- {{ 9 }}\n- {{ 10 }}\n- {{ 11 }}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalTemplated(tt.object, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("MarshalTemplated() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}